@@ -1,20 +1,175 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
+	"os"
 
 	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/config"
 	"github.com/shreyaskaundinya/garlic/pkg/server"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
 )
 
-func StartGarlic(config *models.Config) {
+const (
+	EXIT_OK    = 0
+	EXIT_ERROR = 1
+	EXIT_USAGE = 2
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []*command{
+	{name: "new", summary: "Scaffold a new site in a directory", run: runNew},
+	{name: "build", summary: "Build the site once for production", run: runBuild},
+	{name: "serve", summary: "Build, watch for changes and serve the site", run: runServe},
+	{name: "check", summary: "Validate config, content and templates without writing", run: runCheck},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Garlic : Fast go static site generator\n\n")
+	fmt.Fprintf(os.Stderr, "Usage:\n\n\tgarlic <command> [flags]\n\nCommands:\n\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-8s %s\n", c.name, c.summary)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nUse \"garlic <command> -h\" for more information about a command.\n")
+}
+
+// Execute runs the subcommand named by the first argument and returns the
+// exit code for the process
+func Execute(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return EXIT_USAGE
+	}
+
+	name := args[0]
+
+	switch name {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return EXIT_OK
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "garlic: unknown command %q\n\n", name)
+	usage()
+	return EXIT_USAGE
+}
+
+// StartGarlic builds the site, and when config.ShouldServe is set keeps
+// watching the source folder and serving the destination folder
+func StartGarlic(config *models.Config) error {
+	_, err := server.Seed(config, nil)
+	if err != nil {
+		return fmt.Errorf("error seeding: %w", err)
+	}
+
 	s, err := server.NewServer(config)
+	if err != nil {
+		return err
+	}
+
+	if config.ShouldServe {
+		return s.Start()
+	}
+
+	return s.Build()
+}
+
+// flags shared by the commands that work on an existing site
+type siteFlags struct {
+	srcPath  *string
+	destPath *string
+	baseURL  *string
+	strict   *bool
+	drafts   *bool
+	future   *bool
+
+	// only set for commands that serve the site
+	port *int
+}
+
+func addSiteFlags(fs *flag.FlagSet) *siteFlags {
+	return &siteFlags{
+		srcPath:  fs.String("src-folder", "src", "The source path of the project"),
+		destPath: fs.String("dest-folder", "", "The destination path of the project, overrides dest in the config file"),
+		baseURL:  fs.String("base-url", "", "The absolute url the site is served from"),
+		strict:   fs.Bool("strict", false, "Fail when the frontmatter of a page does not match the schema"),
+		drafts:   fs.Bool("drafts", false, "Render pages marked as draft"),
+		future:   fs.Bool("future", false, "Render pages dated in the future"),
+	}
+}
+
+func newFlagSet(name, args, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: garlic %s %s\n\n%s\n\nFlags:\n", name, args, description)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// -h prints the usage and is not a failure
+func exitCodeForParseError(err error) int {
+	if err == flag.ErrHelp {
+		return EXIT_OK
+	}
+
+	return EXIT_USAGE
+}
+
+// parse the flags and load the config file, flags explicitly set win
+func loadConfig(fs *flag.FlagSet, args []string, sf *siteFlags, flagConfig *models.Config) (*models.Config, int) {
+	log := utils.NewLogger()
 
-	if err != nil || s == nil {
-		// panic(err)
-		fmt.Println("Error: ", err)
-		return
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, exitCodeForParseError(err)
 	}
 
-	s.Start(config)
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %v\n\n", fs.Args())
+		fs.Usage()
+		return nil, EXIT_USAGE
+	}
+
+	setFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	flagConfig.SrcPath = *sf.srcPath
+	flagConfig.DestPath = *sf.destPath
+	flagConfig.BaseURL = *sf.baseURL
+	flagConfig.Strict = *sf.strict
+	flagConfig.BuildDrafts = *sf.drafts
+	flagConfig.BuildFuture = *sf.future
+
+	if sf.port != nil {
+		flagConfig.Port = *sf.port
+	}
+
+	conf, err := config.Load(flagConfig, setFlags)
+	if err != nil {
+		log.Error(err)
+		return nil, EXIT_ERROR
+	}
+
+	log.Infow("Config: ", "config", conf)
+
+	return conf, EXIT_OK
 }
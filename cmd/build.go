@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+func runBuild(args []string) int {
+	log := utils.NewLogger()
+
+	fs := newFlagSet(
+		"build",
+		"[flags]",
+		"Builds the site once into the destination folder, exits with a non-zero code on failure.",
+	)
+	sf := addSiteFlags(fs)
+
+	conf, code := loadConfig(fs, args, sf, &models.Config{})
+	if conf == nil {
+		return code
+	}
+
+	err := StartGarlic(conf)
+	if err != nil {
+		log.Errorw("Build failed", "error", err)
+		return EXIT_ERROR
+	}
+
+	return EXIT_OK
+}
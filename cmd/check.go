@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/server"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+func runCheck(args []string) int {
+	log := utils.NewLogger()
+
+	fs := newFlagSet(
+		"check",
+		"[flags]",
+		"Validates the config, content and templates without writing any output.",
+	)
+	sf := addSiteFlags(fs)
+
+	conf, code := loadConfig(fs, args, sf, &models.Config{DryRun: true})
+	if conf == nil {
+		return code
+	}
+
+	s, err := server.NewServer(conf)
+	if err != nil {
+		log.Errorw("Check failed", "error", err)
+		return EXIT_ERROR
+	}
+
+	err = s.Check()
+	if err != nil {
+		log.Errorw("Check failed", "error", err)
+		return EXIT_ERROR
+	}
+
+	log.Infow("Check passed")
+
+	return EXIT_OK
+}
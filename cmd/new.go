@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	defaultassets "github.com/shreyaskaundinya/garlic/default-assets"
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/config"
+	"github.com/shreyaskaundinya/garlic/pkg/server"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const defaultConfigFile = `# garlic site config, flags passed on the command line win over these values
+dest: ../dest
+title: %q
+baseURL: ""
+port: %d
+`
+
+func runNew(args []string) int {
+	log := utils.NewLogger()
+
+	themes := ""
+	for _, t := range defaultassets.Themes {
+		themes += fmt.Sprintf("\n\t%-10s %s", t.Name, t.Description)
+	}
+
+	fs := newFlagSet(
+		"new",
+		"[flags] <dir>",
+		"Scaffolds a new site: <dir>/src holds the content, templates, components and assets\n"+
+			"and the site is built into <dir>/dest.\n\nThemes:"+themes,
+	)
+	theme := fs.String("theme", defaultassets.DEFAULT_THEME, "The starter theme, a built in theme or the path to a local folder")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return exitCodeForParseError(err)
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return EXIT_USAGE
+	}
+
+	dir := fs.Arg(0)
+
+	// allow flags after the directory, ie: garlic new my-site --theme blog
+	err = fs.Parse(fs.Args()[1:])
+	if err != nil {
+		return exitCodeForParseError(err)
+	}
+
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return EXIT_USAGE
+	}
+
+	themeFS, err := defaultassets.ResolveTheme(*theme)
+	if err != nil {
+		log.Error(err)
+		return EXIT_USAGE
+	}
+
+	conf := &models.Config{
+		SrcPath:         filepath.Join(dir, "src"),
+		DestPath:        filepath.Join(dir, "dest"),
+		ShouldSeedFiles: true,
+	}
+
+	report, err := server.Seed(conf, themeFS)
+	if err != nil {
+		log.Errorw("Error seeding", "error", err)
+		return EXIT_ERROR
+	}
+
+	configPath, err := config.FindConfigFile(conf.SrcPath)
+	if err != nil {
+		log.Errorw("Error looking up config file", "error", err)
+		return EXIT_ERROR
+	}
+
+	if configPath == "" {
+		configPath = filepath.Join(conf.SrcPath, "garlic.yaml")
+
+		body := fmt.Sprintf(defaultConfigFile, filepath.Base(dir), config.DEFAULT_PORT)
+
+		err = os.WriteFile(configPath, []byte(body), 0644)
+		if err != nil {
+			log.Errorw("Error writing config file", "error", err)
+			return EXIT_ERROR
+		}
+	}
+
+	fmt.Printf("Seeded theme %q into %s\n\n", *theme, conf.SrcPath)
+
+	for _, f := range report.Created {
+		fmt.Printf("\tcreated  %s\n", f)
+	}
+
+	for _, f := range report.Skipped {
+		fmt.Printf("\tskipped  %s (already exists)\n", f)
+	}
+
+	fmt.Printf("\nCreated a new site in %s\n\n\tcd %s\n\tgarlic serve\n\n", dir, dir)
+
+	return EXIT_OK
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/config"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+func runServe(args []string) int {
+	log := utils.NewLogger()
+
+	fs := newFlagSet(
+		"serve",
+		"[flags]",
+		"Builds the site, rebuilds it when the source folder changes and serves the\n"+
+			"destination folder with live reload.",
+	)
+	sf := addSiteFlags(fs)
+	sf.port = fs.Int("port", config.DEFAULT_PORT, "The port to serve the project on")
+
+	conf, code := loadConfig(fs, args, sf, &models.Config{ShouldServe: true})
+	if conf == nil {
+		return code
+	}
+
+	err := StartGarlic(conf)
+	if err != nil {
+		log.Errorw("Serve failed", "error", err)
+		return EXIT_ERROR
+	}
+
+	return EXIT_OK
+}
@@ -0,0 +1,9 @@
+package defaultassets
+
+import "embed"
+
+// FS holds the starter themes, each top level folder is a theme whose layout
+// mirrors the source folder of a site
+//
+//go:embed all:default all:blog all:docs all:portfolio
+var FS embed.FS
@@ -0,0 +1,61 @@
+package defaultassets
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+const DEFAULT_THEME = "default"
+
+type Theme struct {
+	// Name
+	Name string
+
+	// Description
+	Description string
+}
+
+// Themes lists the starter themes embedded into the binary
+var Themes = []Theme{
+	{Name: DEFAULT_THEME, Description: "A single page with a navbar and footer"},
+	{Name: "blog", Description: "Home page, posts, an about page and tag pages"},
+	{Name: "docs", Description: "Documentation pages with a sidebar"},
+	{Name: "portfolio", Description: "A landing page with a hero and project pages"},
+}
+
+// ThemeNames returns the names of the embedded themes
+func ThemeNames() []string {
+	names := make([]string, len(Themes))
+	for i, t := range Themes {
+		names[i] = t.Name
+	}
+
+	return names
+}
+
+// ResolveTheme returns the files of a theme, theme is either the name of an
+// embedded theme or the path to a local folder with the same layout
+func ResolveTheme(theme string) (fs.FS, error) {
+	if theme == "" {
+		theme = DEFAULT_THEME
+	}
+
+	for _, t := range Themes {
+		if t.Name == theme {
+			return fs.Sub(FS, t.Name)
+		}
+	}
+
+	info, err := os.Stat(theme)
+	if err == nil && info.IsDir() {
+		return os.DirFS(theme), nil
+	}
+
+	return nil, fmt.Errorf(
+		"theme %q is neither a built in theme (%s) nor a folder",
+		theme,
+		strings.Join(ThemeNames(), ", "),
+	)
+}
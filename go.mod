@@ -3,6 +3,7 @@ module github.com/shreyaskaundinya/garlic
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/aarol/reload v1.2.2
 	github.com/alecthomas/chroma/v2 v2.14.0
 	github.com/deckarep/golang-set/v2 v2.8.0
@@ -11,9 +12,10 @@ require (
 	github.com/puzpuzpuz/xsync/v3 v3.4.0
 	github.com/yuin/goldmark v1.7.4
 	github.com/yuin/goldmark-highlighting/v2 v2.0.0-20230729083705-37449abec8cc
-	github.com/yuin/goldmark-meta v1.1.0
 	go.uber.org/zap v1.27.0
 	golang.org/x/net v0.44.0
+	golang.org/x/text v0.29.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -22,5 +24,4 @@ require (
 	github.com/gorilla/websocket v1.5.3 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
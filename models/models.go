@@ -1,9 +1,201 @@
 package models
 
 type Config struct {
-	SrcPath  string
-	DestPath string
+	SrcPath  string `yaml:"-" toml:"-"`
+	DestPath string `yaml:"dest" toml:"dest"`
 
-	ShouldServe     bool
-	ShouldSeedFiles bool
+	ShouldServe     bool `yaml:"-" toml:"-"`
+	ShouldSeedFiles bool `yaml:"-" toml:"-"`
+
+	// nothing is written to the destination folder (garlic check)
+	DryRun bool `yaml:"-" toml:"-"`
+
+	// render drafts and pages dated in the future (--drafts --future)
+	BuildDrafts bool `yaml:"-" toml:"-"`
+	BuildFuture bool `yaml:"-" toml:"-"`
+
+	// frontmatter not matching the schema fails the build instead of
+	// being reported (--strict)
+	Strict bool `yaml:"-" toml:"-"`
+
+	// absolute url the site is served from, eg: https://example.com
+	BaseURL string `yaml:"baseURL" toml:"baseURL"`
+
+	// site title
+	Title string `yaml:"title" toml:"title"`
+
+	// site author
+	Author string `yaml:"author" toml:"author"`
+
+	// template used when a page does not specify one
+	DefaultTemplate string `yaml:"defaultTemplate" toml:"defaultTemplate"`
+
+	// folder whose templates, components and assets are used when the site
+	// does not have a file with the same name, eg: themes/minimal
+	Theme string `yaml:"theme" toml:"theme"`
+
+	// pages without a `publish` field are rendered, by default they are left
+	// out like pages with `publish: false`
+	PublishByDefault bool `yaml:"publishByDefault" toml:"publishByDefault"`
+
+	// pages without a `lastmod` use the date of the last git commit
+	// touching them instead of the modification time of the file
+	GitInfo bool `yaml:"gitInfo" toml:"gitInfo"`
+
+	// port for the dev server
+	Port int `yaml:"port" toml:"port"`
+
+	// output options
+	Output OutputConfig `yaml:"output" toml:"output"`
+
+	// component options
+	Components ComponentsConfig `yaml:"components" toml:"components"`
+
+	// pagination of list pages (sections, taxonomies)
+	Pagination PaginationConfig `yaml:"pagination" toml:"pagination"`
+
+	// how taxonomy terms are turned into url paths
+	Slug SlugConfig `yaml:"slug" toml:"slug"`
+
+	// headings listed in the table of contents of pages
+	Toc TocConfig `yaml:"toc" toml:"toc"`
+
+	// frontmatter fields expected in the content
+	Schema SchemaConfig `yaml:"schema" toml:"schema"`
+
+	// taxonomies keyed by the frontmatter field holding their terms,
+	// eg: tags, categories, authors
+	Taxonomies map[string]TaxonomyConfig `yaml:"taxonomies" toml:"taxonomies"`
+
+	// rss and atom feeds of the site, its sections and taxonomy terms
+	Feeds FeedsConfig `yaml:"feeds" toml:"feeds"`
+
+	// sitemap.xml listing every page of the site
+	Sitemap SitemapConfig `yaml:"sitemap" toml:"sitemap"`
+
+	// robots.txt pointing crawlers to the sitemap
+	Robots RobotsConfig `yaml:"robots" toml:"robots"`
+
+	// json index of the pages for searching the site in the browser
+	Search SearchConfig `yaml:"search" toml:"search"`
+
+	// custom params, available to templates as $site.params.*
+	Params map[string]any `yaml:"params" toml:"params"`
+}
+
+type OutputConfig struct {
+	// remove everything in the destination folder before a full build
+	Clean bool `yaml:"clean" toml:"clean"`
+}
+
+type ComponentsConfig struct {
+	// how deep components can be nested in other components
+	MaxDepth int `yaml:"maxDepth" toml:"maxDepth"`
+}
+
+type PaginationConfig struct {
+	// pages listed per page, 0 lists every page on a single page
+	PageSize int `yaml:"pageSize" toml:"pageSize"`
+}
+
+type TocConfig struct {
+	// highest heading listed, defaults to 2 (##)
+	MinLevel int `yaml:"minLevel" toml:"minLevel"`
+
+	// deepest heading listed, defaults to 4 (####)
+	MaxLevel int `yaml:"maxLevel" toml:"maxLevel"`
+}
+
+type TaxonomyConfig struct {
+	// url prefix of the taxonomy pages, defaults to the name of the taxonomy
+	Path string `yaml:"path" toml:"path"`
+
+	// template listing every term, defaults to _<name>
+	ListTemplate string `yaml:"listTemplate" toml:"listTemplate"`
+
+	// template listing the pages of a term, defaults to _<name>_term
+	TermTemplate string `yaml:"termTemplate" toml:"termTemplate"`
+}
+
+type FeedsConfig struct {
+	// write the feeds, defaults to true. Feeds need the baseURL of the site.
+	Enabled *bool `yaml:"enabled" toml:"enabled"`
+
+	// items per feed, 0 lists every page
+	Limit int `yaml:"limit" toml:"limit"`
+
+	// items hold the whole rendered page instead of its description
+	FullContent bool `yaml:"fullContent" toml:"fullContent"`
+}
+
+type SitemapConfig struct {
+	// write sitemap.xml, defaults to true. The sitemap needs the baseURL of
+	// the site.
+	Enabled *bool `yaml:"enabled" toml:"enabled"`
+
+	// how often pages change (always, hourly, daily, weekly, monthly, yearly
+	// or never), pages can set their own with `changefreq`
+	ChangeFreq string `yaml:"changefreq" toml:"changefreq"`
+
+	// priority of pages from 0 to 1, pages can set their own with `priority`
+	Priority float64 `yaml:"priority" toml:"priority"`
+}
+
+type RobotsConfig struct {
+	// write robots.txt, defaults to true
+	Enabled *bool `yaml:"enabled" toml:"enabled"`
+
+	// paths crawlers should not visit, eg: /drafts/
+	Disallow []string `yaml:"disallow" toml:"disallow"`
+}
+
+type SearchConfig struct {
+	// write the search index, off by default
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+
+	// fields of each page in the index, defaults to title, sitepath,
+	// description, tags, headings and content. Other frontmatter fields can
+	// be added by name.
+	Fields []string `yaml:"fields" toml:"fields"`
+
+	// characters of the text of a page kept in the index, 0 keeps all of it
+	MaxContentLength int `yaml:"maxContentLength" toml:"maxContentLength"`
+
+	// pages per file of the index, 0 writes a single search.json. Otherwise
+	// search/index.json lists the files holding the pages.
+	ShardSize int `yaml:"shardSize" toml:"shardSize"`
+}
+
+type SlugConfig struct {
+	// lowercase the slugs, defaults to true
+	Lowercase *bool `yaml:"lowercase" toml:"lowercase"`
+
+	// replace accented letters with their ascii base (é -> e), defaults to true
+	Transliterate *bool `yaml:"transliterate" toml:"transliterate"`
+
+	// used between words, defaults to -
+	Separator *string `yaml:"separator" toml:"separator"`
+}
+
+type SchemaConfig struct {
+	// fields of every page, keyed by the frontmatter field
+	Fields map[string]FieldSchema `yaml:"fields" toml:"fields"`
+
+	// fields of the pages of a section (folder under content, ie: blog),
+	// added on top of the fields of every page
+	Sections map[string]map[string]FieldSchema `yaml:"sections" toml:"sections"`
+}
+
+type FieldSchema struct {
+	// string, int, number, bool, date or list, any type when empty
+	Type string `yaml:"type" toml:"type"`
+
+	// the page must set the field
+	Required bool `yaml:"required" toml:"required"`
+
+	// values the field can take
+	Enum []any `yaml:"enum" toml:"enum"`
+
+	// value used when the page does not set the field
+	Default any `yaml:"default" toml:"default"`
 }
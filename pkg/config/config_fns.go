@@ -0,0 +1,534 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const (
+	DEFAULT_PORT                = 8084
+	DEFAULT_COMPONENT_MAX_DEPTH = 16
+	DEFAULT_TAXONOMY            = "tags"
+	DEFAULT_TOC_MIN_LEVEL       = 2
+	DEFAULT_TOC_MAX_LEVEL       = 4
+)
+
+// types of frontmatter fields in the schema
+const (
+	SCHEMA_TYPE_STRING = "string"
+	SCHEMA_TYPE_INT    = "int"
+	SCHEMA_TYPE_NUMBER = "number"
+	SCHEMA_TYPE_BOOL   = "bool"
+	SCHEMA_TYPE_DATE   = "date"
+	SCHEMA_TYPE_LIST   = "list"
+)
+
+var schemaTypes = []string{
+	SCHEMA_TYPE_STRING,
+	SCHEMA_TYPE_INT,
+	SCHEMA_TYPE_NUMBER,
+	SCHEMA_TYPE_BOOL,
+	SCHEMA_TYPE_DATE,
+	SCHEMA_TYPE_LIST,
+}
+
+// fields of the pages in the search index when the config does not list them
+var DefaultSearchFields = []string{
+	"title",
+	"sitepath",
+	"description",
+	"tags",
+	"headings",
+	"content",
+}
+
+// how often pages change, for the sitemap
+var changeFrequencies = []string{
+	"always",
+	"hourly",
+	"daily",
+	"weekly",
+	"monthly",
+	"yearly",
+	"never",
+}
+
+// IsChangeFrequency reports whether value is a changefreq of the sitemap
+// protocol
+func IsChangeFrequency(value string) bool {
+	return slices.Contains(changeFrequencies, value)
+}
+
+// config files looked up in the source folder, first match wins
+var configFileNames = []string{
+	"garlic.yaml",
+	"garlic.yml",
+	"garlic.toml",
+}
+
+// FindConfigFile returns the path of the config file in the source folder,
+// or an empty string if there is none
+func FindConfigFile(srcPath string) (string, error) {
+	for _, name := range configFileNames {
+		p := filepath.Join(srcPath, name)
+
+		exists, err := utils.PathExists(p)
+		if err != nil {
+			return "", err
+		}
+
+		if exists {
+			return p, nil
+		}
+	}
+
+	return "", nil
+}
+
+// ReadConfigFile decodes a yaml or toml config file
+func ReadConfigFile(path string) (*models.Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &models.Config{}
+
+	switch filepath.Ext(path) {
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(b), config)
+		if err == nil && len(md.Undecoded()) > 0 {
+			err = fmt.Errorf("unknown keys %v", md.Undecoded())
+		}
+	default:
+		err = yaml.UnmarshalStrict(b, config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	if config.Params != nil {
+		config.Params = utils.NormalizeValue(config.Params).(map[string]any)
+	}
+
+	normalizeSchemaFields(config.Schema.Fields)
+	for _, fields := range config.Schema.Sections {
+		normalizeSchemaFields(fields)
+	}
+
+	// paths in the config file are relative to the source folder
+	if config.DestPath != "" && !filepath.IsAbs(config.DestPath) {
+		config.DestPath = filepath.Join(filepath.Dir(path), config.DestPath)
+	}
+
+	if config.Theme != "" && !filepath.IsAbs(config.Theme) {
+		config.Theme = filepath.Join(filepath.Dir(path), config.Theme)
+	}
+
+	return config, nil
+}
+
+// Load reads the config file from the source folder of flagConfig and merges
+// it with the values passed as flags. setFlags holds the names of the flags
+// that were explicitly set, those always win over the config file.
+func Load(flagConfig *models.Config, setFlags map[string]bool) (*models.Config, error) {
+	log := utils.NewLogger()
+
+	if flagConfig.SrcPath == "" {
+		return nil, errors.New("config: source folder is required")
+	}
+
+	config := &models.Config{}
+
+	configPath, err := FindConfigFile(flagConfig.SrcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if configPath != "" {
+		log.Infow("Reading config file", "path", configPath)
+
+		config, err = ReadConfigFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	merge(config, flagConfig, setFlags)
+	applyDefaults(config)
+
+	// paths are compared and joined with the paths walked in the source
+	// folder, ./src and src/ have to be the same as src
+	config.SrcPath = utils.CleanPath(config.SrcPath)
+	config.DestPath = utils.CleanPath(config.DestPath)
+	config.Theme = utils.CleanPath(config.Theme)
+
+	err = Validate(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func merge(config *models.Config, flagConfig *models.Config, setFlags map[string]bool) {
+	config.SrcPath = flagConfig.SrcPath
+	config.ShouldServe = flagConfig.ShouldServe
+	config.ShouldSeedFiles = flagConfig.ShouldSeedFiles
+	config.DryRun = flagConfig.DryRun
+	config.BuildDrafts = flagConfig.BuildDrafts
+	config.BuildFuture = flagConfig.BuildFuture
+	config.Strict = flagConfig.Strict
+
+	if setFlags["dest-folder"] || config.DestPath == "" {
+		config.DestPath = flagConfig.DestPath
+	}
+
+	if setFlags["port"] || config.Port == 0 {
+		config.Port = flagConfig.Port
+	}
+
+	if setFlags["base-url"] || config.BaseURL == "" {
+		config.BaseURL = flagConfig.BaseURL
+	}
+}
+
+func applyDefaults(config *models.Config) {
+	if config.Port == 0 {
+		config.Port = DEFAULT_PORT
+	}
+
+	if config.Params == nil {
+		config.Params = make(map[string]any)
+	}
+
+	if config.Components.MaxDepth == 0 {
+		config.Components.MaxDepth = DEFAULT_COMPONENT_MAX_DEPTH
+	}
+
+	if config.Toc.MinLevel == 0 {
+		config.Toc.MinLevel = DEFAULT_TOC_MIN_LEVEL
+	}
+
+	if config.Toc.MaxLevel == 0 {
+		config.Toc.MaxLevel = max(DEFAULT_TOC_MAX_LEVEL, config.Toc.MinLevel)
+	}
+
+	if config.Taxonomies == nil {
+		config.Taxonomies = map[string]models.TaxonomyConfig{
+			DEFAULT_TAXONOMY: {},
+		}
+	}
+
+	for name, taxonomy := range config.Taxonomies {
+		if taxonomy.Path == "" {
+			taxonomy.Path = name
+		}
+		taxonomy.Path = strings.Trim(taxonomy.Path, "/")
+
+		if taxonomy.ListTemplate == "" {
+			taxonomy.ListTemplate = "_" + name
+		}
+
+		if taxonomy.TermTemplate == "" {
+			taxonomy.TermTemplate = "_" + name + "_term"
+
+			// the templates tags have always used
+			if name == DEFAULT_TAXONOMY {
+				taxonomy.TermTemplate = "_individual_tag"
+			}
+		}
+
+		config.Taxonomies[name] = taxonomy
+	}
+
+	if len(config.Search.Fields) == 0 {
+		config.Search.Fields = DefaultSearchFields
+	}
+
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+}
+
+// Validate checks the merged config and reports every problem found
+func Validate(config *models.Config) error {
+	errs := make([]error, 0)
+
+	if config.SrcPath == "" {
+		errs = append(errs, errors.New("source folder is required"))
+	} else if !config.ShouldSeedFiles {
+		// when seeding, the source folder gets created
+		exists, err := utils.PathExists(config.SrcPath)
+		if err != nil || !exists {
+			errs = append(errs, fmt.Errorf("source folder %q does not exist", config.SrcPath))
+		}
+	}
+
+	if config.DestPath == "" && !config.DryRun {
+		errs = append(errs, errors.New("destination folder is required (flag --dest-folder or `dest` in the config file)"))
+	} else if config.DestPath != "" && config.SrcPath != "" {
+		errs = append(errs, validateDest(config)...)
+	}
+
+	if config.Theme != "" {
+		info, err := os.Stat(config.Theme)
+		if err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("theme folder %q does not exist", config.Theme))
+		}
+	}
+
+	if config.Port < 1 || config.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d is out of range (1-65535)", config.Port))
+	}
+
+	if config.BaseURL != "" {
+		u, err := url.Parse(config.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("baseURL %q must be an absolute http(s) url", config.BaseURL))
+		}
+	}
+
+	if config.Components.MaxDepth < 0 {
+		errs = append(errs, fmt.Errorf("components.maxDepth %d must be positive", config.Components.MaxDepth))
+	}
+
+	if config.Pagination.PageSize < 0 {
+		errs = append(errs, fmt.Errorf("pagination.pageSize %d must be positive", config.Pagination.PageSize))
+	}
+
+	if config.Toc.MinLevel < 1 || config.Toc.MaxLevel > 6 || config.Toc.MinLevel > config.Toc.MaxLevel {
+		errs = append(errs, fmt.Errorf(
+			"toc.minLevel %d and toc.maxLevel %d must be heading levels (1-6), minLevel first",
+			config.Toc.MinLevel,
+			config.Toc.MaxLevel,
+		))
+	}
+
+	// feeds are skipped without a baseURL, unless asked for explicitly
+	if config.Feeds.Enabled != nil && *config.Feeds.Enabled && config.BaseURL == "" {
+		errs = append(errs, errors.New("feeds.enabled is true but the site has no baseURL to link the feeds to (`baseURL` in the config or --base-url)"))
+	}
+
+	if config.Feeds.Limit < 0 {
+		errs = append(errs, fmt.Errorf("feeds.limit %d must be positive", config.Feeds.Limit))
+	}
+
+	if config.Search.MaxContentLength < 0 {
+		errs = append(errs, fmt.Errorf("search.maxContentLength %d must be positive", config.Search.MaxContentLength))
+	}
+
+	if config.Search.ShardSize < 0 {
+		errs = append(errs, fmt.Errorf("search.shardSize %d must be positive", config.Search.ShardSize))
+	}
+
+	// the sitemap is skipped without a baseURL, unless asked for explicitly
+	if config.Sitemap.Enabled != nil && *config.Sitemap.Enabled && config.BaseURL == "" {
+		errs = append(errs, errors.New("sitemap.enabled is true but the site has no baseURL for the urls of the sitemap (`baseURL` in the config or --base-url)"))
+	}
+
+	if config.Sitemap.ChangeFreq != "" && !IsChangeFrequency(config.Sitemap.ChangeFreq) {
+		errs = append(errs, fmt.Errorf(
+			"sitemap.changefreq %q must be one of %s",
+			config.Sitemap.ChangeFreq,
+			strings.Join(changeFrequencies, ", "),
+		))
+	}
+
+	if config.Sitemap.Priority < 0 || config.Sitemap.Priority > 1 {
+		errs = append(errs, fmt.Errorf("sitemap.priority %v must be between 0 and 1", config.Sitemap.Priority))
+	}
+
+	for _, disallow := range config.Robots.Disallow {
+		if !strings.HasPrefix(disallow, "/") {
+			errs = append(errs, fmt.Errorf("robots.disallow %q must be a path starting with /", disallow))
+		}
+	}
+
+	if config.Slug.Separator != nil && strings.ContainsAny(*config.Slug.Separator, `/\.`) {
+		errs = append(errs, fmt.Errorf("slug.separator %q must not contain / \\ or .", *config.Slug.Separator))
+	}
+
+	errs = append(errs, validateSchemaFields("schema.fields", config.Schema.Fields)...)
+	for _, section := range slices.Sorted(maps.Keys(config.Schema.Sections)) {
+		errs = append(errs, validateSchemaFields("schema.sections."+section, config.Schema.Sections[section])...)
+	}
+
+	taxonomyPaths := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(config.Taxonomies)) {
+		taxonomyPath := config.Taxonomies[name].Path
+
+		if taxonomyPath == "" || taxonomyPath != path.Clean(taxonomyPath) || strings.HasPrefix(taxonomyPath, "..") {
+			errs = append(errs, fmt.Errorf("taxonomies.%s.path %q must be a relative url path, eg: %s", name, taxonomyPath, name))
+		} else if other, ok := taxonomyPaths[taxonomyPath]; ok {
+			errs = append(errs, fmt.Errorf("taxonomies %s and %s use the same path %q", other, name, taxonomyPath))
+		}
+
+		taxonomyPaths[taxonomyPath] = name
+	}
+
+	if strings.ContainsAny(config.DefaultTemplate, `/\`) || filepath.Ext(config.DefaultTemplate) != "" {
+		errs = append(errs, fmt.Errorf(
+			"defaultTemplate %q must be a template name without folder or extension, eg: index",
+			config.DefaultTemplate,
+		))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// the destination folder is written to (and emptied with output.clean), it
+// must never overlap the source folder
+func validateDest(config *models.Config) []error {
+	errs := make([]error, 0)
+
+	switch {
+	case utils.IsWithin(config.DestPath, config.SrcPath) && utils.IsWithin(config.SrcPath, config.DestPath):
+		errs = append(errs, errors.New("source and destination folders must be different"))
+	case utils.IsWithin(config.SrcPath, config.DestPath):
+		errs = append(errs, fmt.Errorf("destination folder %q must not be inside the source folder", config.DestPath))
+	case config.Output.Clean && utils.IsWithin(config.DestPath, config.SrcPath):
+		errs = append(errs, fmt.Errorf(
+			"destination folder %q contains the source folder, output.clean would delete it",
+			config.DestPath,
+		))
+	}
+
+	return errs
+}
+
+func validateSchemaFields(prefix string, fields map[string]models.FieldSchema) []error {
+	errs := make([]error, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		field := fields[name]
+
+		if field.Type != "" && !slices.Contains(schemaTypes, field.Type) {
+			errs = append(errs, fmt.Errorf(
+				"%s.%s.type %q must be one of %s",
+				prefix,
+				name,
+				field.Type,
+				strings.Join(schemaTypes, ", "),
+			))
+			continue
+		}
+
+		// the default is set on pages without the field, it has to pass the
+		// checks of the field like any value written in a page
+		if field.Default != nil {
+			err := CheckField(field, field.Default)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s.default: %w", prefix, name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// defaults and enums are decoded like params, ie: a yaml map default is a
+// map[any]any until normalized
+func normalizeSchemaFields(fields map[string]models.FieldSchema) {
+	for name, field := range fields {
+		field.Default = utils.NormalizeValue(field.Default)
+		for i, value := range field.Enum {
+			field.Enum[i] = utils.NormalizeValue(value)
+		}
+
+		fields[name] = field
+	}
+}
+
+// CheckField checks a frontmatter value against the type and the enum of its
+// schema field
+func CheckField(field models.FieldSchema, value any) error {
+	ok := true
+
+	switch field.Type {
+	case SCHEMA_TYPE_STRING:
+		_, ok = value.(string)
+	case SCHEMA_TYPE_INT:
+		switch value.(type) {
+		case int, int64, uint64:
+		default:
+			ok = false
+		}
+	case SCHEMA_TYPE_NUMBER:
+		switch value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			ok = false
+		}
+	case SCHEMA_TYPE_BOOL:
+		_, ok = value.(bool)
+	case SCHEMA_TYPE_DATE:
+		_, ok = utils.ParseTime(value)
+	case SCHEMA_TYPE_LIST:
+		_, ok = value.([]any)
+	}
+
+	if !ok {
+		return fmt.Errorf("%v is not a %s", value, field.Type)
+	}
+
+	if len(field.Enum) > 0 && !slices.ContainsFunc(field.Enum, func(allowed any) bool {
+		return fmt.Sprint(allowed) == fmt.Sprint(value)
+	}) {
+		allowed := make([]string, len(field.Enum))
+		for i, v := range field.Enum {
+			allowed[i] = fmt.Sprint(v)
+		}
+
+		return fmt.Errorf("%v is not one of %s", value, strings.Join(allowed, ", "))
+	}
+
+	return nil
+}
+
+// SlugOptions returns the options used to slugify taxonomy terms, options
+// left out of the config keep their defaults
+func SlugOptions(config *models.Config) utils.SlugOptions {
+	options := utils.DefaultSlugOptions
+
+	if config.Slug.Lowercase != nil {
+		options.Lowercase = *config.Slug.Lowercase
+	}
+
+	if config.Slug.Transliterate != nil {
+		options.Transliterate = *config.Slug.Transliterate
+	}
+
+	if config.Slug.Separator != nil {
+		options.Separator = *config.Slug.Separator
+	}
+
+	return options
+}
+
+// SiteData returns the config values exposed to templates as {{ $site.* }},
+// custom params are available as {{ $site.params.<key> }}
+func SiteData(config *models.Config) map[string]any {
+	return map[string]any{
+		"title":   config.Title,
+		"author":  config.Author,
+		"baseURL": config.BaseURL,
+		"params":  config.Params,
+	}
+}
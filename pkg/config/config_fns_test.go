@@ -0,0 +1,353 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shreyaskaundinya/garlic/models"
+)
+
+// a source folder with an optional garlic.yaml, returns the folder holding it
+func testSite(t *testing.T, configFile string) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(root, "src"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if configFile != "" {
+		err = os.WriteFile(filepath.Join(root, "src", "garlic.yaml"), []byte(configFile), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func TestLoadCleansPaths(t *testing.T) {
+	root := testSite(t, "")
+
+	tests := []struct {
+		name    string
+		srcPath string
+	}{
+		{name: "clean", srcPath: filepath.Join(root, "src")},
+		{name: "trailing slash", srcPath: filepath.Join(root, "src") + string(filepath.Separator)},
+		{name: "dot segments", srcPath: filepath.Join(root, ".", "src", "..", "src")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Load(&models.Config{
+				SrcPath:  tt.srcPath,
+				DestPath: filepath.Join(root, "dest") + string(filepath.Separator),
+			}, map[string]bool{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := filepath.Join(root, "src"); config.SrcPath != want {
+				t.Errorf("got src %q, want %q", config.SrcPath, want)
+			}
+
+			if want := filepath.Join(root, "dest"); config.DestPath != want {
+				t.Errorf("got dest %q, want %q", config.DestPath, want)
+			}
+
+			if config.Theme != "" {
+				t.Errorf("got theme %q, want none", config.Theme)
+			}
+		})
+	}
+}
+
+func TestLoadOutputsNeedBaseURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		baseURL    string
+		wantErr    string
+	}{
+		{
+			name:       "left to the defaults",
+			configFile: "title: site\n",
+		},
+		{
+			name:       "feeds disabled",
+			configFile: "feeds:\n  enabled: false\n",
+		},
+		{
+			name:       "feeds enabled with a baseURL",
+			configFile: "baseURL: https://example.com\nfeeds:\n  enabled: true\n",
+		},
+		{
+			name:       "feeds enabled with a baseURL flag",
+			configFile: "feeds:\n  enabled: true\n",
+			baseURL:    "https://example.com",
+		},
+		{
+			name:       "feeds enabled without a baseURL",
+			configFile: "feeds:\n  enabled: true\n",
+			wantErr:    "feeds.enabled is true but the site has no baseURL",
+		},
+		{
+			name:       "sitemap disabled",
+			configFile: "sitemap:\n  enabled: false\n",
+		},
+		{
+			name:       "sitemap enabled with a baseURL",
+			configFile: "baseURL: https://example.com\nsitemap:\n  enabled: true\n",
+		},
+		{
+			name:       "sitemap enabled without a baseURL",
+			configFile: "sitemap:\n  enabled: true\n",
+			wantErr:    "sitemap.enabled is true but the site has no baseURL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testSite(t, tt.configFile)
+
+			_, err := Load(&models.Config{
+				SrcPath:  filepath.Join(root, "src"),
+				DestPath: filepath.Join(root, "dest"),
+				BaseURL:  tt.baseURL,
+			}, map[string]bool{"base-url": tt.baseURL != ""})
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSchemaDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		want       any
+		wantErr    string
+	}{
+		{
+			name:       "default of the field type",
+			configFile: "schema:\n  fields:\n    layout:\n      type: string\n      default: wide\n",
+			want:       "wide",
+		},
+		{
+			name:       "default in the enum",
+			configFile: "schema:\n  fields:\n    layout:\n      enum: [wide, narrow]\n      default: narrow\n",
+			want:       "narrow",
+		},
+		{
+			name:       "map default is normalized",
+			configFile: "schema:\n  fields:\n    author:\n      default:\n        name: me\n",
+			want:       map[string]any{"name": "me"},
+		},
+		{
+			name:       "default of another type",
+			configFile: "schema:\n  fields:\n    weight:\n      type: int\n      default: heavy\n",
+			wantErr:    "schema.fields.weight.default: heavy is not a int",
+		},
+		{
+			name:       "default not in the enum",
+			configFile: "schema:\n  fields:\n    layout:\n      enum: [wide, narrow]\n      default: full\n",
+			wantErr:    "schema.fields.layout.default: full is not one of wide, narrow",
+		},
+		{
+			name:       "section default",
+			configFile: "schema:\n  sections:\n    blog:\n      draft:\n        type: bool\n        default: \"no\"\n",
+			wantErr:    "schema.sections.blog.draft.default: no is not a bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testSite(t, tt.configFile)
+
+			config, err := Load(&models.Config{
+				SrcPath:  filepath.Join(root, "src"),
+				DestPath: filepath.Join(root, "dest"),
+			}, map[string]bool{})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, field := range config.Schema.Fields {
+				if !reflect.DeepEqual(field.Default, tt.want) {
+					t.Errorf("got default %#v, want %#v", field.Default, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	flags := &models.Config{
+		SrcPath:  "src",
+		DestPath: "flag-dest",
+		Port:     8080,
+		BaseURL:  "https://flag.example.com",
+	}
+
+	tests := []struct {
+		name     string
+		config   models.Config
+		setFlags map[string]bool
+		want     models.Config
+	}{
+		{
+			name:     "flag defaults fill what the config leaves out",
+			config:   models.Config{},
+			setFlags: map[string]bool{},
+			want:     models.Config{DestPath: "flag-dest", Port: 8080, BaseURL: "https://flag.example.com"},
+		},
+		{
+			name:     "config wins over flag defaults",
+			config:   models.Config{DestPath: "dest", Port: 3000, BaseURL: "https://example.com"},
+			setFlags: map[string]bool{},
+			want:     models.Config{DestPath: "dest", Port: 3000, BaseURL: "https://example.com"},
+		},
+		{
+			name:     "set flags win over the config",
+			config:   models.Config{DestPath: "dest", Port: 3000, BaseURL: "https://example.com"},
+			setFlags: map[string]bool{"dest-folder": true, "port": true, "base-url": true},
+			want:     models.Config{DestPath: "flag-dest", Port: 8080, BaseURL: "https://flag.example.com"},
+		},
+		{
+			name:     "only the set flag wins",
+			config:   models.Config{DestPath: "dest", Port: 3000, BaseURL: "https://example.com"},
+			setFlags: map[string]bool{"port": true},
+			want:     models.Config{DestPath: "dest", Port: 8080, BaseURL: "https://example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			merge(&config, flags, tt.setFlags)
+
+			if config.SrcPath != flags.SrcPath {
+				t.Errorf("got src %q, want %q", config.SrcPath, flags.SrcPath)
+			}
+
+			if config.DestPath != tt.want.DestPath {
+				t.Errorf("got dest %q, want %q", config.DestPath, tt.want.DestPath)
+			}
+
+			if config.Port != tt.want.Port {
+				t.Errorf("got port %d, want %d", config.Port, tt.want.Port)
+			}
+
+			if config.BaseURL != tt.want.BaseURL {
+				t.Errorf("got baseURL %q, want %q", config.BaseURL, tt.want.BaseURL)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	root := testSite(t, "")
+	src := filepath.Join(root, "src")
+
+	tests := []struct {
+		name    string
+		config  models.Config
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: models.Config{SrcPath: src, DestPath: filepath.Join(root, "dest"), Port: 8080},
+		},
+		{
+			name:   "dry run without a destination",
+			config: models.Config{SrcPath: src, DryRun: true, Port: 8080},
+		},
+		{
+			name:    "missing source folder",
+			config:  models.Config{SrcPath: filepath.Join(root, "nope"), DestPath: filepath.Join(root, "dest"), Port: 8080},
+			wantErr: "does not exist",
+		},
+		{
+			name:    "missing destination",
+			config:  models.Config{SrcPath: src, Port: 8080},
+			wantErr: "destination folder is required",
+		},
+		{
+			name:    "destination is the source",
+			config:  models.Config{SrcPath: src, DestPath: src, Port: 8080},
+			wantErr: "source and destination folders must be different",
+		},
+		{
+			name:    "destination inside the source",
+			config:  models.Config{SrcPath: src, DestPath: filepath.Join(src, "public"), Port: 8080},
+			wantErr: "must not be inside the source folder",
+		},
+		{
+			name:   "source inside the destination",
+			config: models.Config{SrcPath: src, DestPath: root, Port: 8080},
+		},
+		{
+			name: "source inside a destination that is cleaned",
+			config: models.Config{
+				SrcPath:  src,
+				DestPath: root,
+				Port:     8080,
+				Output:   models.OutputConfig{Clean: true},
+			},
+			wantErr: "output.clean would delete it",
+		},
+		{
+			name:    "port out of range",
+			config:  models.Config{SrcPath: src, DestPath: filepath.Join(root, "dest"), Port: 70000},
+			wantErr: "port 70000 is out of range",
+		},
+		{
+			name:    "relative baseURL",
+			config:  models.Config{SrcPath: src, DestPath: filepath.Join(root, "dest"), Port: 8080, BaseURL: "example.com"},
+			wantErr: "must be an absolute http(s) url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			applyDefaults(&config)
+
+			err := Validate(&config)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// extensions of the files read from the data folder
+var DataExtensions = []string{".yaml", ".yml", ".json", ".toml", ".csv"}
+
+// ParseData decodes a data file by its extension into the same types as the
+// frontmatter. The rows of a csv file become maps keyed by its header row.
+func ParseData(path string, body []byte) (any, error) {
+	var value any
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(body, &value)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		err = decoder.Decode(&value)
+	case ".toml":
+		values := map[string]any{}
+		err = toml.Unmarshal(body, &values)
+		value = values
+	case ".csv":
+		value, err = parseCSV(body)
+	default:
+		return nil, fmt.Errorf("unsupported data file %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing data file %s: %w", path, err)
+	}
+
+	return normalizeFrontmatterValue(value), nil
+}
+
+func parseCSV(body []byte) ([]any, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\ufeff"))))
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]any, 0, len(records))
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -1,6 +1,21 @@
 package parser
 
-import "github.com/shreyaskaundinya/garlic/pkg/utils"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+var (
+	yamlDelimiter = []byte("---")
+	tomlDelimiter = []byte("+++")
+)
 
 type Frontmatter struct {
 	Store map[string]any
@@ -27,24 +42,162 @@ func (f *Frontmatter) Set(key string, value any) {
 }
 
 func (f *Frontmatter) GetTags() []string {
-	log := utils.NewLogger()
+	return f.GetTerms("tags")
+}
 
-	tags, ok := f.Store["tags"]
-	if !ok {
-		log.Errorw("Tags not found in frontmatter", "frontmatter", *f)
-		return []string{}
+// GetTerms returns the terms of a taxonomy (tags, categories, ...), a single
+// term can be written as a plain string
+func (f *Frontmatter) GetTerms(key string) []string {
+	switch terms := f.Store[key].(type) {
+	case string:
+		if terms == "" {
+			return []string{}
+		}
+		return []string{terms}
+	case []any:
+		termsStrings := make([]string, 0, len(terms))
+		for _, term := range terms {
+			if term := utils.GetSafeValue[string](term); term != "" {
+				termsStrings = append(termsStrings, term)
+			}
+		}
+		return termsStrings
 	}
 
-	tagsArray, ok := tags.([]any)
-	if !ok {
-		log.Errorw("Tags not found in frontmatter", "frontmatter", *f)
-		return []string{}
+	return []string{}
+}
+
+// SplitFrontmatter separates the frontmatter at the top of a file from the
+// rest of it, files without one get an empty frontmatter and the body as is.
+// The frontmatter can be yaml between --- lines or toml between +++ lines,
+// values are decoded into the same types whatever the format.
+func SplitFrontmatter(body []byte) (*Frontmatter, []byte, error) {
+	frontmatter := NewFrontmatter()
+
+	trimmed := bytes.TrimLeft(body, "\ufeff \t\r\n")
+
+	lines := bytes.SplitAfter(trimmed, []byte("\n"))
+
+	var delimiter []byte
+	switch {
+	case bytes.Equal(bytes.TrimSpace(lines[0]), yamlDelimiter):
+		delimiter = yamlDelimiter
+	case bytes.Equal(bytes.TrimSpace(lines[0]), tomlDelimiter):
+		delimiter = tomlDelimiter
+	default:
+		return frontmatter, body, nil
 	}
 
-	tagsStrings := make([]string, len(tagsArray))
-	for i, tag := range tagsArray {
-		tagsStrings[i] = utils.GetSafeValue[string](tag)
+	for i := 1; i < len(lines); i++ {
+		if !bytes.Equal(bytes.TrimSpace(lines[i]), delimiter) {
+			continue
+		}
+
+		raw := bytes.Join(lines[1:i], nil)
+
+		values := map[string]any{}
+
+		var err error
+		if bytes.Equal(delimiter, tomlDelimiter) {
+			err = toml.Unmarshal(raw, &values)
+		} else {
+			err = yaml.Unmarshal(raw, &values)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing frontmatter: %w", err)
+		}
+
+		for key, value := range values {
+			frontmatter.Set(key, normalizeFrontmatterValue(value))
+		}
+
+		return frontmatter, bytes.Join(lines[i+1:], nil), nil
+	}
+
+	return nil, nil, fmt.Errorf("frontmatter is not closed with %s", delimiter)
+}
+
+// SplitContentFrontmatter is SplitFrontmatter for markdown files, which can
+// also start with a json object as their frontmatter
+func SplitContentFrontmatter(body []byte) (*Frontmatter, []byte, error) {
+	trimmed := bytes.TrimLeft(body, "\ufeff \t\r\n")
+
+	if bytes.HasPrefix(trimmed, []byte("{")) && !bytes.HasPrefix(trimmed, []byte("{{")) {
+		return splitJSONFrontmatter(trimmed)
+	}
+
+	return SplitFrontmatter(body)
+}
+
+// a json object at the top of the file, the body starts after its closing }
+func splitJSONFrontmatter(body []byte) (*Frontmatter, []byte, error) {
+	frontmatter := NewFrontmatter()
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	values := map[string]any{}
+	err := decoder.Decode(&values)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing frontmatter: %w", err)
+	}
+
+	for key, value := range values {
+		frontmatter.Set(key, normalizeFrontmatterValue(value))
+	}
+
+	return frontmatter, body[decoder.InputOffset():], nil
+}
+
+// values decode to the types the yaml frontmatter has always had: whole
+// numbers are ints, dates are strings, lists are []any and maps are
+// map[string]any
+func normalizeFrontmatterValue(value any) any {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case time.Time:
+		// toml marks dates and times without a timezone with these zones
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format(time.DateOnly)
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05")
+		case "time-local":
+			return v.Format(time.TimeOnly)
+		}
+		return v.Format(time.RFC3339)
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[key] = normalizeFrontmatterValue(val)
+		}
+		return m
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = normalizeFrontmatterValue(val)
+		}
+		return m
+	case []any:
+		l := make([]any, len(v))
+		for i, val := range v {
+			l[i] = normalizeFrontmatterValue(val)
+		}
+		return l
+	case []map[string]any:
+		l := make([]any, len(v))
+		for i, val := range v {
+			l[i] = normalizeFrontmatterValue(val)
+		}
+		return l
 	}
 
-	return tagsStrings
+	return value
 }
@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitFrontmatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     map[string]any
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "yaml",
+			body:     "---\ntitle: Home\ncount: 3\n---\n# Home\n",
+			want:     map[string]any{"title": "Home", "count": 3},
+			wantBody: "# Home\n",
+		},
+		{
+			name:     "toml",
+			body:     "+++\ntitle = \"Home\"\ncount = 3\n+++\n# Home\n",
+			want:     map[string]any{"title": "Home", "count": 3},
+			wantBody: "# Home\n",
+		},
+		{
+			name:     "toml local date",
+			body:     "+++\ndate = 2024-01-02\n+++\n",
+			want:     map[string]any{"date": "2024-01-02"},
+			wantBody: "",
+		},
+		{
+			name:     "byte order mark",
+			body:     "\ufeff---\ntitle: Home\n---\nbody",
+			want:     map[string]any{"title": "Home"},
+			wantBody: "body",
+		},
+		{
+			name:     "blank lines and crlf",
+			body:     "\r\n\r\n---\r\ntitle: Home\r\n---\r\nbody",
+			want:     map[string]any{"title": "Home"},
+			wantBody: "body",
+		},
+		{
+			name:     "empty frontmatter",
+			body:     "---\n---\nbody",
+			want:     map[string]any{},
+			wantBody: "body",
+		},
+		{
+			name:     "no frontmatter",
+			body:     "# Home\n---\n",
+			want:     map[string]any{},
+			wantBody: "# Home\n---\n",
+		},
+		{
+			name:     "template at the start of the body",
+			body:     "{{ $page.title }}\n",
+			want:     map[string]any{},
+			wantBody: "{{ $page.title }}\n",
+		},
+		{
+			name:    "delimiters do not mix",
+			body:    "+++\ntitle = \"Home\"\n---\n+++\nbody",
+			wantErr: "error parsing frontmatter",
+		},
+		{
+			name:    "unclosed yaml",
+			body:    "---\ntitle: Home\n",
+			wantErr: "frontmatter is not closed with ---",
+		},
+		{
+			name:    "unclosed toml",
+			body:    "+++\ntitle = \"Home\"\n# Home\n",
+			wantErr: "frontmatter is not closed with +++",
+		},
+		{
+			name:    "invalid yaml",
+			body:    "---\ntitle: [Home\n---\n",
+			wantErr: "error parsing frontmatter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatter, body, err := SplitFrontmatter([]byte(tt.body))
+			checkSplit(t, frontmatter, body, err, tt.want, tt.wantBody, tt.wantErr)
+		})
+	}
+}
+
+func TestSplitContentFrontmatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     map[string]any
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "json",
+			body:     "{\"title\": \"Home\", \"count\": 3, \"ratio\": 0.5}\n# Home\n",
+			want:     map[string]any{"title": "Home", "count": 3, "ratio": 0.5},
+			wantBody: "\n# Home\n",
+		},
+		{
+			name:     "json with byte order mark",
+			body:     "\ufeff{\"title\": \"Home\"}body",
+			want:     map[string]any{"title": "Home"},
+			wantBody: "body",
+		},
+		{
+			name:     "json with nested values",
+			body:     "{\"tags\": [\"a\", 1], \"author\": {\"name\": \"me\"}}",
+			want:     map[string]any{"tags": []any{"a", 1}, "author": map[string]any{"name": "me"}},
+			wantBody: "",
+		},
+		{
+			name:     "template at the start of the body is not json",
+			body:     "{{ $page.title }}\n",
+			want:     map[string]any{},
+			wantBody: "{{ $page.title }}\n",
+		},
+		{
+			name:     "yaml",
+			body:     "---\ntitle: Home\n---\nbody",
+			want:     map[string]any{"title": "Home"},
+			wantBody: "body",
+		},
+		{
+			name:    "unclosed json",
+			body:    "{\"title\": \"Home\"\n# Home\n",
+			wantErr: "error parsing frontmatter",
+		},
+		{
+			name:    "unclosed toml",
+			body:    "+++\ntitle = \"Home\"\n",
+			wantErr: "frontmatter is not closed with +++",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatter, body, err := SplitContentFrontmatter([]byte(tt.body))
+			checkSplit(t, frontmatter, body, err, tt.want, tt.wantBody, tt.wantErr)
+		})
+	}
+}
+
+func checkSplit(
+	t *testing.T,
+	frontmatter *Frontmatter,
+	body []byte,
+	err error,
+	want map[string]any,
+	wantBody string,
+	wantErr string,
+) {
+	t.Helper()
+
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("got error %v, want %q", err, wantErr)
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(frontmatter.Store, want) {
+		t.Errorf("got frontmatter %#v, want %#v", frontmatter.Store, want)
+	}
+
+	if string(body) != wantBody {
+		t.Errorf("got body %q, want %q", body, wantBody)
+	}
+}
+
+func TestNormalizeFrontmatterValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "int64", value: int64(3), want: 3},
+		{name: "json whole number", value: json.Number("3"), want: 3},
+		{name: "json decimal", value: json.Number("0.5"), want: 0.5},
+		{name: "string", value: "Home", want: "Home"},
+		{name: "bool", value: true, want: true},
+		{name: "nil", value: nil, want: nil},
+		{
+			name:  "time with a zone",
+			value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			want:  "2024-01-02T03:04:05Z",
+		},
+		{
+			name:  "toml local date",
+			value: time.Date(2024, 1, 2, 0, 0, 0, 0, time.FixedZone("date-local", 0)),
+			want:  "2024-01-02",
+		},
+		{
+			name:  "toml local datetime",
+			value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("datetime-local", 0)),
+			want:  "2024-01-02T03:04:05",
+		},
+		{
+			name:  "toml local time",
+			value: time.Date(0, 1, 1, 3, 4, 5, 0, time.FixedZone("time-local", 0)),
+			want:  "03:04:05",
+		},
+		{
+			name:  "yaml map",
+			value: map[any]any{"name": "me", 1: int64(2)},
+			want:  map[string]any{"name": "me", "1": 2},
+		},
+		{
+			name:  "nested list",
+			value: []any{int64(1), []any{json.Number("2")}, map[string]any{"a": int64(3)}},
+			want:  []any{1, []any{2}, map[string]any{"a": 3}},
+		},
+		{
+			name:  "toml array of tables",
+			value: []map[string]any{{"name": "a", "count": int64(1)}},
+			want:  []any{map[string]any{"name": "a", "count": 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeFrontmatterValue(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"time"
+
 	"github.com/puzpuzpuz/xsync/v3"
 )
 
@@ -17,6 +19,15 @@ type Meta struct {
 	// Tags
 	Tags []string
 
+	// Date of the page, `date` in the frontmatter
+	Date time.Time
+
+	// when the page goes live, `publishDate` in the frontmatter
+	PublishDate time.Time
+
+	// last change, `lastmod` in the frontmatter
+	LastModified time.Time
+
 	// File
 	F *File
 
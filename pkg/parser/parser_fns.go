@@ -7,7 +7,6 @@ import (
 	mathjax "github.com/litao91/goldmark-mathjax"
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
-	meta "github.com/yuin/goldmark-meta"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
@@ -17,9 +16,6 @@ import (
 func NewParser() *Parser {
 	md := goldmark.New(
 		goldmark.WithExtensions(
-			meta.New(
-				meta.WithStoresInDocument(),
-			),
 			extension.GFM,
 			highlighting.NewHighlighting(
 				highlighting.WithStyle("monokai"),
@@ -46,22 +42,22 @@ func NewParser() *Parser {
 	}
 }
 
-// parse file and sets the parsed node, return the metadata
-func (p *Parser) Parse(file *File) *Frontmatter {
+// parse file and sets the parsed node, return the metadata. The frontmatter
+// (yaml, toml or json) is cut from the body before parsing the markdown.
+func (p *Parser) Parse(file *File) (*Frontmatter, error) {
+	frontmatter, body, err := SplitContentFrontmatter(file.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Body = body
+
 	// ctx := parser.NewContext()
 	node := p.parser.Parse(text.NewReader(file.Body))
 
 	file.Node = node
 
-	meta := node.OwnerDocument().Meta()
-
-	frontmatter := NewFrontmatter()
-
-	for key, value := range meta {
-		frontmatter.Set(key, value)
-	}
-
-	return frontmatter
+	return frontmatter, nil
 }
 
 // render file and return the rendered bytes
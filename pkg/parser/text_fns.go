@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+type Heading struct {
+	// 1 for #, 2 for ##, ...
+	Level int
+
+	// id set by the auto heading ids, used as the anchor of the heading
+	ID string
+
+	// text of the heading without markup
+	Text string
+}
+
+// Headings returns the headings of a parsed file in the order they appear
+func Headings(file *File) []Heading {
+	headings := make([]Heading, 0)
+
+	if file.Node == nil {
+		return headings
+	}
+
+	_ = ast.Walk(file.Node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+
+		headings = append(headings, Heading{
+			Level: heading.Level,
+			ID:    string(idBytes),
+			Text:  nodeText(heading, file.Body),
+		})
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	return headings
+}
+
+// PlainText returns the text of a parsed file without its markup, blocks are
+// separated by new lines and raw html is left out
+func PlainText(file *File) string {
+	if file.Node == nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	_ = ast.Walk(file.Node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch node := n.(type) {
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			if entering {
+				lines := node.Lines()
+				for i := 0; i < lines.Len(); i++ {
+					segment := lines.At(i)
+					b.Write(segment.Value(file.Body))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				writeText(&b, node, file.Body)
+			}
+		case *ast.String:
+			if entering {
+				b.Write(node.Value)
+			}
+		}
+
+		if !entering && n.Type() == ast.TypeBlock && b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(b.String())
+}
+
+// text of the inline children of a node, ie: the text of a heading
+func nodeText(n ast.Node, source []byte) string {
+	var b strings.Builder
+
+	_ = ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			writeText(&b, node, source)
+		case *ast.String:
+			b.Write(node.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(b.String())
+}
+
+func writeText(b *strings.Builder, text *ast.Text, source []byte) {
+	b.Write(text.Segment.Value(source))
+
+	if text.SoftLineBreak() || text.HardLineBreak() {
+		b.WriteString(" ")
+	}
+}
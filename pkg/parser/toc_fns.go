@@ -0,0 +1,37 @@
+package parser
+
+// a heading in the table of contents with the headings nested under it
+type TocEntry struct {
+	Heading
+
+	Children []*TocEntry
+}
+
+// TableOfContents nests the headings between minLevel and maxLevel under the
+// closest heading above them with a lower level. A heading skipping levels
+// (## then ####) is nested right under the previous one.
+func TableOfContents(headings []Heading, minLevel int, maxLevel int) []*TocEntry {
+	root := &TocEntry{}
+
+	// the entries the next heading can be nested under, root first
+	stack := []*TocEntry{root}
+
+	for _, heading := range headings {
+		if heading.Level < minLevel || heading.Level > maxLevel {
+			continue
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].Level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		entry := &TocEntry{Heading: heading}
+
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, entry)
+
+		stack = append(stack, entry)
+	}
+
+	return root.Children
+}
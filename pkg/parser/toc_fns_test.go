@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// headings written as "2:a 3:b", level then text
+func testHeadings(spec string) []Heading {
+	headings := make([]Heading, 0)
+
+	for _, field := range strings.Fields(spec) {
+		level, text, _ := strings.Cut(field, ":")
+		headings = append(headings, Heading{
+			Level: int(level[0] - '0'),
+			ID:    text,
+			Text:  text,
+		})
+	}
+
+	return headings
+}
+
+// entries written as "a(b c(d)) e"
+func formatToc(entries []*TocEntry) string {
+	parts := make([]string, len(entries))
+
+	for i, entry := range entries {
+		parts[i] = entry.Text
+		if len(entry.Children) > 0 {
+			parts[i] += "(" + formatToc(entry.Children) + ")"
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestTableOfContents(t *testing.T) {
+	tests := []struct {
+		name     string
+		headings string
+		minLevel int
+		maxLevel int
+		want     string
+	}{
+		{
+			name:     "no headings",
+			headings: "",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "",
+		},
+		{
+			name:     "flat",
+			headings: "2:a 2:b 2:c",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a b c",
+		},
+		{
+			name:     "nested",
+			headings: "2:a 3:b 3:c 2:d 3:e",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a(b c) d(e)",
+		},
+		{
+			name:     "skipped level is nested under the previous heading",
+			headings: "2:a 4:b 3:c",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a(b c)",
+		},
+		{
+			name:     "skipped level then back to the top",
+			headings: "2:a 4:b 2:c",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a(b) c",
+		},
+		{
+			name:     "deeper heading first",
+			headings: "3:a 2:b 3:c",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a b(c)",
+		},
+		{
+			name:     "deep first heading stays at the top",
+			headings: "4:a 3:b 4:c",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a b(c)",
+		},
+		{
+			name:     "levels outside the range are left out",
+			headings: "1:title 2:a 5:x 3:b 6:y",
+			minLevel: 2,
+			maxLevel: 4,
+			want:     "a(b)",
+		},
+		{
+			name:     "headings under a left out heading move up",
+			headings: "1:title 2:a 1:other 2:b",
+			minLevel: 2,
+			maxLevel: 3,
+			want:     "a b",
+		},
+		{
+			name:     "single level",
+			headings: "2:a 3:b 2:c",
+			minLevel: 2,
+			maxLevel: 2,
+			want:     "a c",
+		},
+		{
+			name:     "every level",
+			headings: "1:a 2:b 3:c 4:d 5:e 6:f",
+			minLevel: 1,
+			maxLevel: 6,
+			want:     "a(b(c(d(e(f)))))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatToc(TableOfContents(testHeadings(tt.headings), tt.minLevel, tt.maxLevel))
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
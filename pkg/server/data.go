@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/templating"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const DATA_FOLDER = "data"
+
+// read the files of the data folder into the {{ $data }} namespace, the path
+// of a file is its key: data/authors.yaml is $data.authors and
+// data/team/leads.csv is $data.team.leads
+func (s *Server) readData() error {
+	log := utils.NewLogger()
+
+	start := time.Now()
+
+	defer func() {
+		log.Debugw("Time taken to read data", "time", time.Since(start))
+	}()
+
+	data := map[string]any{}
+
+	// the files of the site are read last so that they win over the theme
+	for _, dataPath := range s.layerPaths(DATA_FOLDER) {
+		err := filepath.WalkDir(dataPath, func(path string, info os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() || !slices.Contains(parser.DataExtensions, strings.ToLower(filepath.Ext(path))) {
+				return nil
+			}
+
+			body, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			value, err := parser.ParseData(path, body)
+			if err != nil {
+				return err
+			}
+
+			relativePath, err := filepath.Rel(dataPath, path)
+			if err != nil {
+				return err
+			}
+
+			keys := strings.Split(filepath.ToSlash(utils.FileNameWithoutExtension(relativePath)), "/")
+
+			return setData(data, keys, value)
+		})
+		if err != nil {
+			return fmt.Errorf("error reading data: %w", err)
+		}
+	}
+
+	s.Data = data
+
+	return nil
+}
+
+// set the value at the nested keys, creating the maps of the folders on the
+// way. A file whose name is also a folder must hold a map, their keys merge.
+func setData(data map[string]any, keys []string, value any) error {
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := data[key]
+		if !ok {
+			next = map[string]any{}
+			data[key] = next
+		}
+
+		nextMap, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("data %s is both a file and a folder", strings.Join(keys[:i+1], "."))
+		}
+
+		data = nextMap
+	}
+
+	key := keys[len(keys)-1]
+
+	existing, ok := data[key].(map[string]any)
+	if !ok {
+		data[key] = value
+		return nil
+	}
+
+	valueMap, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("data %s is both a file and a folder", strings.Join(keys, "."))
+	}
+
+	for k, v := range valueMap {
+		existing[k] = v
+	}
+
+	return nil
+}
+
+// whether rendering a page reads {{ $data }}, either from its templates or
+// from a component (which could be used by any page)
+func (s *Server) dependsOnData(page *parser.Meta) bool {
+	usesData := func(body []byte) bool {
+		return bytes.Contains(body, []byte("$data"))
+	}
+
+	componentUsesData := false
+	s.ComponentsMD.Store.Range(func(_ string, component *parser.Meta) bool {
+		componentUsesData = usesData(component.F.Body)
+		return !componentUsesData
+	})
+	if componentUsesData {
+		return true
+	}
+
+	t, err := s.resolveTemplate(page)
+	if err != nil {
+		// rendering reports the error
+		return true
+	}
+
+	layers, err := s.templateLayers(t)
+	if err != nil {
+		return true
+	}
+
+	return slices.ContainsFunc(layers, func(layer templating.Layer) bool {
+		return usesData(layer.Body)
+	})
+}
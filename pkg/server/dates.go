@@ -0,0 +1,77 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+// read the date of the last commit of every file of the site when the
+// config asks for it, pages fall back to the modification time of their file
+// if git cannot be read
+func (s *Server) readGitDates() {
+	log := utils.NewLogger()
+
+	if !s.Config.GitInfo {
+		return
+	}
+
+	dates, err := utils.GitLastModified(s.SrcPath)
+	if err != nil {
+		log.Warnw("Error reading git dates, using file modification times", "error", err)
+		dates = nil
+	}
+
+	s.gitDates = dates
+}
+
+// set the dates of a page:
+//   - Date is `date`, else `publishDate`, else the last modification
+//   - PublishDate is `publishDate`, else the date
+//   - LastModified is `lastmod`, else the last git commit touching the file
+//     (with gitInfo), else the modification time of the file
+func (s *Server) setDates(markdownMeta *parser.Meta) {
+	lastModified, ok := frontmatterTime(markdownMeta, "lastmod")
+	if !ok {
+		lastModified = s.fileLastModified(markdownMeta.F.Path)
+	}
+
+	date, ok := frontmatterTime(markdownMeta, "date")
+	publishDate, publishOk := frontmatterTime(markdownMeta, "publishDate")
+
+	if !ok {
+		date = lastModified
+		if publishOk {
+			date = publishDate
+		}
+	}
+
+	if !publishOk {
+		publishDate = date
+	}
+
+	markdownMeta.Date = date
+	markdownMeta.PublishDate = publishDate
+	markdownMeta.LastModified = lastModified
+}
+
+func (s *Server) fileLastModified(path string) time.Time {
+	if s.gitDates != nil {
+		absPath, err := filepath.Abs(path)
+		if err == nil {
+			if date, ok := s.gitDates[absPath]; ok {
+				return date
+			}
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
@@ -0,0 +1,350 @@
+package server
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const (
+	RSS_FEED_FILE  = "index.xml"
+	ATOM_FEED_FILE = "atom.xml"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Description string  `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published,omitempty"`
+	Updated   string      `xml:"updated"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   *atomText   `xml:"summary,omitempty"`
+	Content   *atomText   `xml:"content,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// a feed before it is written as rss and atom
+type feed struct {
+	Title    string
+	Sitepath string
+	Pages    []*parser.Meta
+}
+
+// the values of a page shared by both feed formats
+type feedItem struct {
+	Page    *parser.Meta
+	URL     string
+	Author  string
+	Content string
+}
+
+// feeds are written unless disabled, links in them have to be absolute so
+// the site needs a baseURL
+func (s *Server) feedsEnabled() bool {
+	if s.Config.Feeds.Enabled != nil && !*s.Config.Feeds.Enabled {
+		return false
+	}
+
+	return s.Config.BaseURL != ""
+}
+
+// write the feed of the whole site, holding every published page but the
+// list pages of sections
+func (s *Server) processFeeds() error {
+	log := utils.NewLogger()
+
+	if !s.feedsEnabled() {
+		log.Debugw("Feeds are disabled or the site has no baseURL, skipping them")
+		return nil
+	}
+
+	pages := make([]*parser.Meta, 0)
+	s.MD.Range(func(path string, value *parser.Meta) bool {
+		if !isSectionIndex(path) && s.isPublished(value) {
+			pages = append(pages, value)
+		}
+		return true
+	})
+
+	err := s.writeFeeds(&feed{
+		Title:    s.Config.Title,
+		Sitepath: "/",
+		Pages:    pages,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infow("Processed feeds", "pages", len(pages))
+
+	return nil
+}
+
+// write the rss and atom feeds of a list of pages next to its list page,
+// newest pages first
+func (s *Server) writeFeeds(f *feed) error {
+	if !s.feedsEnabled() {
+		return nil
+	}
+
+	pages := slices.Clone(f.Pages)
+	sortPages(pages, SORT_BY_DATE, "")
+
+	if limit := s.Config.Feeds.Limit; limit > 0 && len(pages) > limit {
+		pages = pages[:limit]
+	}
+
+	items := make([]feedItem, 0, len(pages))
+	for _, page := range pages {
+		item, err := s.feedItem(page)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, item)
+	}
+
+	destPath := filepath.Join(s.DestPath, filepath.FromSlash(f.Sitepath))
+
+	err := os.MkdirAll(destPath, 0755)
+	if err != nil {
+		return err
+	}
+
+	rss, err := marshalXML(s.rssFeed(f, items))
+	if err != nil {
+		return fmt.Errorf("error writing the rss feed of %s: %w", f.Sitepath, err)
+	}
+
+	err = os.WriteFile(filepath.Join(destPath, RSS_FEED_FILE), rss, 0644)
+	if err != nil {
+		return err
+	}
+
+	atom, err := marshalXML(s.atomFeed(f, items))
+	if err != nil {
+		return fmt.Errorf("error writing the atom feed of %s: %w", f.Sitepath, err)
+	}
+
+	return os.WriteFile(filepath.Join(destPath, ATOM_FEED_FILE), atom, 0644)
+}
+
+func (s *Server) feedItem(page *parser.Meta) (feedItem, error) {
+	author, _ := page.Frontmatter.Get("author")
+
+	item := feedItem{
+		Page:    page,
+		URL:     s.absoluteURL(page.Sitepath),
+		Author:  utils.GetSafeValue[string](author),
+		Content: page.Description,
+	}
+
+	if s.Config.Feeds.FullContent {
+		content, err := s.renderContent(page)
+		if err != nil {
+			return item, fmt.Errorf("%s: %w", page.F.Path, err)
+		}
+
+		item.Content = content
+	}
+
+	return item, nil
+}
+
+func (s *Server) rssFeed(f *feed, items []feedItem) *rssFeed {
+	channel := rssChannel{
+		Title:       s.feedTitle(f),
+		Link:        s.absoluteURL(f.Sitepath),
+		Description: s.feedTitle(f),
+		Self: atomLink{
+			Href: s.absoluteURL(path.Join(f.Sitepath, RSS_FEED_FILE)),
+			Rel:  "self",
+			Type: "application/rss+xml",
+		},
+		Items: make([]rssItem, len(items)),
+	}
+
+	if len(items) > 0 {
+		channel.LastBuildDate = feedUpdated(items).Format(time.RFC1123Z)
+	}
+
+	for i, item := range items {
+		channel.Items[i] = rssItem{
+			Title:       item.Page.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{IsPermaLink: true, Value: item.URL},
+			Description: item.Content,
+		}
+
+		if !item.Page.PublishDate.IsZero() {
+			channel.Items[i].PubDate = item.Page.PublishDate.Format(time.RFC1123Z)
+		}
+	}
+
+	return &rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	}
+}
+
+func (s *Server) atomFeed(f *feed, items []feedItem) *atomFeed {
+	atom := &atomFeed{
+		Title:   s.feedTitle(f),
+		ID:      s.absoluteURL(f.Sitepath),
+		Updated: feedUpdated(items).Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: s.absoluteURL(f.Sitepath), Rel: "alternate", Type: "text/html"},
+			{Href: s.absoluteURL(path.Join(f.Sitepath, ATOM_FEED_FILE)), Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: make([]atomEntry, len(items)),
+	}
+
+	// atom requires an author, on the feed or on every entry
+	if s.Config.Author != "" {
+		atom.Author = &atomAuthor{Name: s.Config.Author}
+	}
+
+	for i, item := range items {
+		entry := atomEntry{
+			Title:   item.Page.Title,
+			ID:      item.URL,
+			Link:    atomLink{Href: item.URL, Rel: "alternate", Type: "text/html"},
+			Updated: item.Page.LastModified.Format(time.RFC3339),
+		}
+
+		if !item.Page.PublishDate.IsZero() {
+			entry.Published = item.Page.PublishDate.Format(time.RFC3339)
+		}
+
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		} else if atom.Author == nil {
+			entry.Author = &atomAuthor{Name: s.Config.Title}
+		}
+
+		if item.Content != "" {
+			text := &atomText{Type: "html", Value: item.Content}
+			if s.Config.Feeds.FullContent {
+				entry.Content = text
+			} else {
+				entry.Summary = text
+			}
+		}
+
+		atom.Entries[i] = entry
+	}
+
+	return atom
+}
+
+// title of a feed, the title of the site is added to the title of the list
+func (s *Server) feedTitle(f *feed) string {
+	switch {
+	case f.Title == "":
+		return s.Config.Title
+	case s.Config.Title == "" || f.Title == s.Config.Title:
+		return f.Title
+	}
+
+	return fmt.Sprintf("%s | %s", f.Title, s.Config.Title)
+}
+
+// the last change of any item of a feed
+func feedUpdated(items []feedItem) time.Time {
+	pages := make([]*parser.Meta, len(items))
+	for i, item := range items {
+		pages[i] = item.Page
+	}
+
+	updated := latestChange(pages)
+	if updated.IsZero() {
+		return time.Now()
+	}
+
+	return updated
+}
+
+// url of a sitepath on the site, ie: /posts/ -> https://example.com/posts/
+func (s *Server) absoluteURL(sitepath string) string {
+	return s.Config.BaseURL + sitepath
+}
+
+func marshalXML(v any) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&b)
+	encoder.Indent("", "  ")
+
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	b.WriteString("\n")
+
+	return b.Bytes(), nil
+}
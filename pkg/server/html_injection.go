@@ -4,213 +4,478 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 
 	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/templating"
 	"github.com/shreyaskaundinya/garlic/pkg/utils"
 )
 
-func (s *Server) recursivelyReplaceTags(
-	tagTemplate *html.Node,
-	fileMetadata *parser.Meta,
-) {
-	log := utils.NewLogger()
+// components are written with an uppercase first letter, ie: <Navbar>
+const COMPONENT_NAME_PATTERN = `[A-Z][A-Za-z0-9_-]*`
 
-	log.Infow("Recursively replacing tags")
+// self closing components and slots, ie: <Navbar /> or <slot name="x" />
+var selfClosingTagRegex = regexp.MustCompile(
+	`<(` + COMPONENT_NAME_PATTERN + `|slot)((?:\s+[^\s>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>'"]+))?)*)\s*/>`,
+)
 
-	// generate li tags for each tag
-	tags := fileMetadata.Tags
+// opening and closing tags of components, ie: <Navbar ...> or </Navbar>
+var componentTagRegex = regexp.MustCompile(`<(/?)(` + COMPONENT_NAME_PATTERN + `)([\s/>])`)
 
-	log.Infow("Tags", "tags", tags)
+// the attribute of the <template> element standing in for a component until
+// it is expanded, holds the name of the component
+const COMPONENT_ATTR = "data-garlic-component"
 
-	liTags := make([]*html.Node, 0)
+// whether a tag names a component, html tags are not case sensitive so <BR>
+// is a <br> unless the site has a Br.html component
+func (s *Server) isComponentTag(name string) bool {
+	_, ok := s.ComponentsMD.Get(strings.ToLower(name))
+	return ok
+}
 
-	for _, tag := range tags {
-		li := &html.Node{
-			Type: html.ElementNode,
-			Data: "li",
+// prepare html for the parser before the components in it are expanded:
+//   - self closing components and slots are closed, the html parser ignores
+//     the / of a self closing tag that is not a void element so <Navbar />
+//     would swallow everything after it as its children
+//   - components become <template data-garlic-component="navbar"> elements,
+//     the only element the parser keeps where it is in any context (ie: a
+//     <Row> in a <tbody> is not moved out of the table), and which keeps a
+//     component named like a void element (<Img>) from being parsed as one
+func (s *Server) prepareComponents(body string) string {
+	body = selfClosingTagRegex.ReplaceAllStringFunc(body, func(tag string) string {
+		match := selfClosingTagRegex.FindStringSubmatch(tag)
+		if match[1] != "slot" && !s.isComponentTag(match[1]) {
+			return tag
 		}
 
-		a := &html.Node{
-			Type: html.ElementNode,
-			Data: "a",
+		return fmt.Sprintf("<%s%s></%s>", match[1], match[2], match[1])
+	})
+
+	return componentTagRegex.ReplaceAllStringFunc(body, func(tag string) string {
+		match := componentTagRegex.FindStringSubmatch(tag)
+		if !s.isComponentTag(match[2]) {
+			return tag
 		}
 
-		a.Attr = append(a.Attr, html.Attribute{
-			Key: "href",
-			Val: fmt.Sprintf("/tags/%s", tag),
-		})
+		if match[1] == "/" {
+			return "</template" + match[3]
+		}
 
-		a.AppendChild(&html.Node{
-			Type: html.TextNode,
-			Data: tag,
-		})
+		return fmt.Sprintf(`<template %s="%s"%s`, COMPONENT_ATTR, strings.ToLower(match[2]), match[3])
+	})
+}
 
-		li.AppendChild(a)
+// the component an element stands in for, see prepareComponents
+func (s *Server) elementComponent(n *html.Node) (*parser.Meta, bool) {
+	if n.Type != html.ElementNode || n.DataAtom != atom.Template {
+		return nil, false
+	}
 
-		liTags = append(liTags, li)
+	name, ok := getAttr(n, COMPONENT_ATTR)
+	if !ok {
+		return nil, false
 	}
 
-	for child := range tagTemplate.Descendants() {
-		// log.Infow("Child", "child", child.Data)
-		switch child.Type {
-		case html.TextNode:
-			switch {
-			case strings.Contains(child.Data, "{{ $tags }}"):
-				log.Infow("Injecting tags into child node")
-				for _, li := range liTags {
-					// log.Infow("Appending li tag", "li", li.Data)
-					child.Parent.AppendChild(li)
-				}
+	return s.ComponentsMD.Get(name)
+}
 
-				// log.Infow("Removing child node", "child", child.Data)
-				child.Parent.RemoveChild(child)
+// an explicit <head> tag, ie: <head> or <HEAD lang="en">
+var headTagRegex = regexp.MustCompile(`(?i)<head[\s>]`)
 
-			}
+// <template> is allowed in the <head>, so in a template without a <head> tag
+// the parser puts the components used before any content in the head it
+// creates. Like any other element written there they belong to the body.
+func (s *Server) moveLeadingComponents(doc *html.Node, source string) {
+	if headTagRegex.MatchString(source) {
+		return
+	}
+
+	var head, body *html.Node
+	for n := range doc.Descendants() {
+		switch n.DataAtom {
+		case atom.Head:
+			head = n
+		case atom.Body:
+			body = n
+		}
+	}
+
+	if head == nil || body == nil {
+		return
+	}
+
+	first := head.FirstChild
+	for first != nil {
+		if _, ok := s.elementComponent(first); ok {
+			break
 		}
+		first = first.NextSibling
 	}
 
-	log.Infow("Tags injected")
+	// everything from the first component on, in order, before the content
+	bodyStart := body.FirstChild
+	for first != nil {
+		next := first.NextSibling
+		head.RemoveChild(first)
+		body.InsertBefore(first, bodyStart)
+		first = next
+	}
 }
 
-func (s *Server) recursivelyReplace(
-	templateHTML *html.Node,
-	contentHTML *html.Node,
-	fileMetadata *parser.Meta,
-	tagsTemplate *html.Node,
-) {
-	log := utils.NewLogger()
-
-	log.Infow("Recursively replacing", "node", templateHTML.Data)
-
-	for child := range templateHTML.Descendants() {
-		// log.Infow("Child", "child", child.Data)
-
-		switch child.Type {
-		case html.TextNode:
-			switch {
-			case strings.Contains(child.Data, "{{ $content }}"):
-				// log.Infow("Injecting content")
-
-				// remove the {{ $content }} from the child.Data
-				child.Parent.InsertBefore(contentHTML, child)
-				child.Parent.RemoveChild(child)
-			case strings.Contains(child.Data, "{{ $title }}"):
-				// log.Infow("Injecting title")
-
-				// remove the {{ $title }} from the child.Data
-				child.Data = strings.ReplaceAll(
-					child.Data, "{{ $title }}", utils.GetSafeValue[string](fileMetadata.Title),
-				)
-			}
-		case html.ElementNode:
-			// skip if the element is a content element
-			if len(child.Attr) > 0 && child.Attr[0].Key == "x-type" && child.Attr[0].Val == "content" {
-				continue
+func getAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+func removeAttr(n *html.Node, key string) {
+	attrs := n.Attr[:0]
+	for _, attr := range n.Attr {
+		if attr.Key != key {
+			attrs = append(attrs, attr)
+		}
+	}
+	n.Attr = attrs
+}
+
+// move the children of the component element into the <slot> elements of the
+// rendered component: children with a slot="name" attribute go to the slot
+// with that name, the rest go to the default (unnamed) slot. Slots that
+// receive nothing keep their own children as fallback content.
+func fillSlots(component *html.Node, element *html.Node) {
+	slots := make([]*html.Node, 0)
+	for n := range component.Descendants() {
+		if n.Type == html.ElementNode && n.Data == "slot" {
+			slots = append(slots, n)
+		}
+	}
+
+	named := map[string]bool{}
+	for _, slot := range slots {
+		if name, ok := getAttr(slot, "name"); ok {
+			named[name] = true
+		}
+	}
+
+	// collect the children of the element per slot name, "" is the default
+	assigned := map[string][]*html.Node{}
+	for element.FirstChild != nil {
+		child := element.FirstChild
+		element.RemoveChild(child)
+
+		name := ""
+		if child.Type == html.ElementNode {
+			if slotName, ok := getAttr(child, "slot"); ok && named[slotName] {
+				name = slotName
+				removeAttr(child, "slot")
 			}
+		}
 
-			if child.Data == "tags" {
-				child.Parent.InsertBefore(tagsTemplate, child)
-				child.Parent.RemoveChild(child)
-			} else {
-				// find child.Data in s.ComponentsMD
-				component, ok := s.ComponentsMD.Get(strings.ToLower(child.Data))
-				if ok {
-					// log.Infow("Found component", "component", child.Data)
-
-					// parse the component
-					parsedComponent, err := html.Parse(bytes.NewReader(component.F.Body))
-					if err != nil {
-						return
-					}
-
-					child.Parent.InsertBefore(parsedComponent, child)
-					// child.Parent.RemoveChild(child)
-				}
+		assigned[name] = append(assigned[name], child)
+	}
+
+	for _, slot := range slots {
+		name, _ := getAttr(slot, "name")
+
+		nodes, ok := assigned[name]
+		if !ok {
+			// fallback content
+			for slot.FirstChild != nil {
+				child := slot.FirstChild
+				slot.RemoveChild(child)
+				nodes = append(nodes, child)
 			}
 		}
+
+		// content is only inserted once, even if the slot is repeated
+		delete(assigned, name)
+
+		for _, n := range nodes {
+			slot.Parent.InsertBefore(n, slot)
+		}
+		slot.Parent.RemoveChild(slot)
 	}
 }
 
-func (s *Server) injectHTML(
-	fileMetadata *parser.Meta,
-	mdHTML bytes.Buffer,
-) (string, error) {
-	log := utils.NewLogger()
+// name of a component as written in its file name, used in errors
+func componentName(component *parser.Meta) string {
+	return strings.TrimSuffix(filepath.Base(component.F.Path), ".html")
+}
 
-	template, ok := fileMetadata.Frontmatter.Get("template")
-	if !ok {
-		return "", fmt.Errorf("template not found")
+// the closest element a component gets inserted into, <body> when there is
+// none (ie: a component used at the top level of another component)
+func insertionContext(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode {
+			return p
+		}
 	}
 
-	t, ok := s.TemplateMD.Get(
+	return &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	}
+}
+
+// collect the outermost component elements under n, components nested in
+// the children of another component are expanded together with it
+func (s *Server) collectComponents(n *html.Node, elements []*html.Node) []*html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if _, ok := s.elementComponent(child); ok {
+			elements = append(elements, child)
+			continue
+		}
+
+		elements = s.collectComponents(child, elements)
+	}
+
+	return elements
+}
+
+// replace the custom elements in the template (<Navbar></Navbar>) with their
+// components, components are templates too and see the same data. Components
+// used inside components are expanded to any depth, stack holds the
+// components being expanded to catch cycles.
+func (s *Server) recursivelyReplace(
+	templateHTML *html.Node,
+	data map[string]any,
+	stack []string,
+) error {
+	// collect the elements first, the tree changes while replacing them
+	elements := s.collectComponents(templateHTML, make([]*html.Node, 0))
+
+	for _, child := range elements {
+		component, _ := s.elementComponent(child)
+		name := componentName(component)
+
+		if slices.Contains(stack, name) {
+			return fmt.Errorf(
+				"component cycle: %s",
+				strings.Join(append(slices.Clone(stack), name), " -> "),
+			)
+		}
+
+		if len(stack) >= s.Config.Components.MaxDepth {
+			return fmt.Errorf(
+				"components nested deeper than %d: %s",
+				s.Config.Components.MaxDepth,
+				strings.Join(append(slices.Clone(stack), name), " -> "),
+			)
+		}
+
+		// the children belong to the caller, expand them at its level
+		err := s.recursivelyReplace(child, data, stack)
+		if err != nil {
+			return err
+		}
+
+		componentHTML, err := templating.Render(
+			component.F.Path,
+			component.F.Body,
+			componentData(data, component, child),
+			s.Filters,
+		)
+		if err != nil {
+			return err
+		}
+
+		// parse the component as a fragment of the element it is inserted
+		// into, parsing it as a document would wrap it in <html><body>
+		context := insertionContext(child)
+
+		nodes, err := html.ParseFragment(
+			strings.NewReader(s.prepareComponents(componentHTML)),
+			context,
+		)
+		if err != nil {
+			return fmt.Errorf("error parsing component %s: %w", component.F.Path, err)
+		}
+
+		// hold the fragment in a copy of the context so that components
+		// used at its top level are parsed against the same context
+		parsedComponent := &html.Node{
+			Type:     html.ElementNode,
+			Data:     context.Data,
+			DataAtom: context.DataAtom,
+		}
+		for _, n := range nodes {
+			parsedComponent.AppendChild(n)
+		}
+
+		// expand the components used by this component
+		err = s.recursivelyReplace(parsedComponent, data, append(slices.Clone(stack), name))
+		if err != nil {
+			return err
+		}
+
+		fillSlots(parsedComponent, child)
+
+		for parsedComponent.FirstChild != nil {
+			n := parsedComponent.FirstChild
+			parsedComponent.RemoveChild(n)
+			child.Parent.InsertBefore(n, child)
+		}
+		child.Parent.RemoveChild(child)
+	}
+
+	return nil
+}
+
+// look up a template by the name used in frontmatter, ie: "post" or "base"
+func (s *Server) getTemplate(name string) (*parser.Meta, bool) {
+	return s.TemplateMD.Get(
 		filepath.Join(
 			s.SrcPath,
 			"templates",
-			fmt.Sprintf("%s.html", utils.GetSafeValue[string](template)),
+			fmt.Sprintf("%s.html", name),
 		),
 	)
+}
 
-	if !ok {
-		return "", fmt.Errorf("template not found")
+// find the template of a page, in order: the template named in its
+// frontmatter, the default of its section (templates/blog.html or
+// templates/blog/_default.html for content/blog/*, the closest section
+// first) and the defaultTemplate of the config
+func (s *Server) resolveTemplate(page *parser.Meta) (*parser.Meta, error) {
+	if templateName, ok := page.Frontmatter.Get("template"); ok {
+		name := utils.GetSafeValue[string](templateName)
+
+		t, ok := s.getTemplate(name)
+		if !ok {
+			return nil, fmt.Errorf("template %s not found", name)
+		}
+
+		return t, nil
 	}
 
-	// st := string(t.F.Body)
+	tried := make([]string, 0)
 
-	// parse the templates html
-	parsedTemplate, err := html.Parse(bytes.NewReader(t.F.Body))
-	if err != nil {
-		return "", fmt.Errorf("error parsing template: %w", err)
+	contentPath, err := filepath.Rel(filepath.Join(s.SrcPath, "content"), page.F.Path)
+	if err == nil {
+		for section := filepath.Dir(contentPath); section != "."; section = filepath.Dir(section) {
+			for _, name := range []string{section, filepath.Join(section, "_default")} {
+				if t, ok := s.getTemplate(name); ok {
+					return t, nil
+				}
+				tried = append(tried, filepath.Join("templates", name+".html"))
+			}
+		}
 	}
 
-	parsedMDHTML, err := html.Parse(bytes.NewReader(mdHTML.Bytes()))
-	if err != nil {
-		return "", fmt.Errorf("error parsing md html: %w", err)
+	if s.Config.DefaultTemplate != "" {
+		if t, ok := s.getTemplate(s.Config.DefaultTemplate); ok {
+			return t, nil
+		}
+		tried = append(tried, filepath.Join("templates", s.Config.DefaultTemplate+".html"))
 	}
 
-	parsedMDHTML.Attr = append(parsedMDHTML.Attr, html.Attribute{
-		Key: "x-type",
-		Val: "content",
-	})
+	if len(tried) == 0 {
+		return nil, fmt.Errorf("no template set in frontmatter and no defaultTemplate in config")
+	}
 
-	// if we find <Tags />, we need to inject the tags from the file metadata
-	// load the tags template
-	tagsTemplateFile, ok := s.ComponentsMD.Get(
-		"tags",
+	return nil, fmt.Errorf(
+		"no template set in frontmatter and none of the defaults found: %s",
+		strings.Join(tried, ", "),
 	)
-	if !ok {
-		return "", fmt.Errorf("tags template not found")
+}
+
+// follow the extends of a template up to its base layout, the layers start
+// with the base layout and end with the template itself
+func (s *Server) templateLayers(t *parser.Meta) ([]templating.Layer, error) {
+	layers := []templating.Layer{{Name: t.F.Path, Body: t.F.Body}}
+	names := []string{strings.TrimSuffix(filepath.Base(t.F.Path), ".html")}
+
+	for {
+		extends, ok := t.Frontmatter.Get("extends")
+		if !ok {
+			break
+		}
+
+		name := utils.GetSafeValue[string](extends)
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf(
+				"template cycle: %s",
+				strings.Join(append(names, name), " -> "),
+			)
+		}
+
+		parent, ok := s.getTemplate(name)
+		if !ok {
+			return nil, fmt.Errorf(
+				"template %s extends %s which is not found",
+				names[len(names)-1],
+				name,
+			)
+		}
+
+		t = parent
+		names = append(names, name)
+		layers = append([]templating.Layer{{Name: t.F.Path, Body: t.F.Body}}, layers...)
 	}
 
-	log.Infow("Tags Template File", "tagsTemplateBody", string(tagsTemplateFile.F.Body))
+	return layers, nil
+}
 
-	// parse the tags template
-	tagsTemplate, err := html.Parse(bytes.NewReader(tagsTemplateFile.F.Body))
+// execute a template (and the layouts it extends), then expand the
+// components used in its output
+func (s *Server) renderTemplate(
+	layers []templating.Layer,
+	data map[string]any,
+) (string, error) {
+	out, err := templating.RenderLayers(layers, data, s.Filters)
 	if err != nil {
-		return "", fmt.Errorf("error parsing tags template: %w", err)
+		return "", err
 	}
 
-	// replace tags in template
-	s.recursivelyReplaceTags(tagsTemplate, fileMetadata)
+	// parse the templates html
+	parsedTemplate, err := html.Parse(strings.NewReader(s.prepareComponents(out)))
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %w", err)
+	}
 
-	s.recursivelyReplace(
-		parsedTemplate,
-		parsedMDHTML,
-		fileMetadata,
-		tagsTemplate,
-	)
+	s.moveLeadingComponents(parsedTemplate, out)
+
+	err = s.recursivelyReplace(parsedTemplate, data, nil)
+	if err != nil {
+		return "", err
+	}
 
 	contentBuffer := bytes.NewBuffer(make([]byte, 0))
-	html.Render(contentBuffer, parsedTemplate)
+	err = html.Render(contentBuffer, parsedTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	return contentBuffer.String(), nil
+}
 
-	content := contentBuffer.String()
+// render a page into its template, data is the data of the page
+// (see templateData)
+func (s *Server) injectHTML(
+	fileMetadata *parser.Meta,
+	data map[string]any,
+) (string, error) {
+	t, err := s.resolveTemplate(fileMetadata)
+	if err != nil {
+		return "", err
+	}
 
-	// content := strings.ReplaceAll(st, "{{ $content }}", mdHTML.String())
-	// content = strings.ReplaceAll(content, "{{ $title }}", utils.GetSafeValue[string](fileMetadata.Title))
+	layers, err := s.templateLayers(t)
+	if err != nil {
+		return "", err
+	}
 
-	return content, nil
+	return s.renderTemplate(layers, data)
 }
 
 func (s *Server) injectComponents(
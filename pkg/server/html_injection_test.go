@@ -0,0 +1,388 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/templating"
+)
+
+// a server with components registered like readComponents does, components
+// are given by file name without the extension, ie: "Navbar"
+func testServer(t *testing.T, components map[string]string) *Server {
+	t.Helper()
+
+	s, err := NewServer(&models.Config{
+		Components: models.ComponentsConfig{MaxDepth: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, body := range components {
+		frontmatter, body, err := parser.SplitFrontmatter([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s.ComponentsMD.Set(strings.ToLower(name), &parser.Meta{
+			Title:       strings.ToLower(name),
+			F:           &parser.File{Path: name + ".html", Body: body},
+			Tags:        make([]string, 0),
+			Frontmatter: frontmatter,
+		})
+	}
+
+	return s
+}
+
+// render a template body with the components of the server, the html of the
+// <body> is returned
+func renderBody(t *testing.T, s *Server, body string) (string, error) {
+	t.Helper()
+
+	out, err := s.renderTemplate(
+		[]templating.Layer{{Name: "page.html", Body: []byte(body)}},
+		map[string]any{},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	_, out, _ = strings.Cut(out, "<body>")
+	out, _, _ = strings.Cut(out, "</body>")
+
+	return out, nil
+}
+
+func TestRenderTemplateHTMLTags(t *testing.T) {
+	s := testServer(t, map[string]string{
+		"Img": `<figure><img src="{{ $props.src }}"/></figure>`,
+	})
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "uppercase html tag",
+			body: `<p>a<BR>b</p>`,
+			want: `<p>a<br/>b</p>`,
+		},
+		{
+			name: "self closing uppercase html tag",
+			body: `<p>a<BR />b</p>`,
+			want: `<p>a<br/>b</p>`,
+		},
+		{
+			name: "uppercase element",
+			body: `<DIV class="x">a</DIV>`,
+			want: `<div class="x">a</div>`,
+		},
+		{
+			name: "component named like a void element",
+			body: `<Img src="a.png" /><img src="b.png"/>`,
+			want: `<figure><img src="a.png"/></figure><img src="b.png"/>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBody(t, s, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateUppercaseHead(t *testing.T) {
+	s := testServer(t, nil)
+
+	out, err := s.renderTemplate(
+		[]templating.Layer{{
+			Name: "page.html",
+			Body: []byte(`<HTML><HEAD><TITLE>x</TITLE></HEAD><BODY>y</BODY></HTML>`),
+		}},
+		map[string]any{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<html><head><title>x</title></head><body>y</body></html>`
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestRenderTemplateComponentContexts(t *testing.T) {
+	s := testServer(t, map[string]string{
+		"Row":    `<tr><td>{{ $props.x }}</td></tr>`,
+		"Rows":   `<Row x="1" /><Row x="2" />`,
+		"Cell":   `<td>{{ $props.x }}</td>`,
+		"Item":   `<li>{{ $props.name }}</li>`,
+		"Option": `<option value="{{ $props.value }}">{{ $props.value }}</option>`,
+		"Header": `<header>top</header>`,
+	})
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "row in a table body",
+			body: `<table><tbody><Row x="1" /></tbody></table>`,
+			want: `<table><tbody><tr><td>1</td></tr></tbody></table>`,
+		},
+		{
+			name: "rows from a component in a table body",
+			body: `<table><tbody><Rows /></tbody></table>`,
+			want: `<table><tbody><tr><td>1</td></tr><tr><td>2</td></tr></tbody></table>`,
+		},
+		{
+			name: "cell in a row",
+			body: `<table><tbody><tr><Cell x="1" /><td>2</td></tr></tbody></table>`,
+			want: `<table><tbody><tr><td>1</td><td>2</td></tr></tbody></table>`,
+		},
+		{
+			name: "items in a list",
+			body: `<ul><Item name="a" /><Item name="b" /></ul>`,
+			want: `<ul><li>a</li><li>b</li></ul>`,
+		},
+		{
+			name: "option in a select",
+			body: `<select><Option value="a" /></select>`,
+			want: `<select><option value="a">a</option></select>`,
+		},
+		{
+			name: "component before any content",
+			body: `<Header /><p>text</p>`,
+			want: `<header>top</header><p>text</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBody(t, s, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// html parsed as the children of a <div>
+func parseChildren(t *testing.T, body string) *html.Node {
+	t.Helper()
+
+	div := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+
+	nodes, err := html.ParseFragment(strings.NewReader(body), div)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range nodes {
+		div.AppendChild(n)
+	}
+
+	return div
+}
+
+func TestFillSlots(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string
+		children  string
+		want      string
+	}{
+		{
+			name:      "default slot",
+			component: `<div class="card"><slot></slot></div>`,
+			children:  `<p>hello</p>`,
+			want:      `<div class="card"><p>hello</p></div>`,
+		},
+		{
+			name:      "no slot drops the children",
+			component: `<div class="card"></div>`,
+			children:  `<p>hello</p>`,
+			want:      `<div class="card"></div>`,
+		},
+		{
+			name:      "named slots",
+			component: `<header><slot name="title"></slot></header><main><slot></slot></main>`,
+			children:  `<h1 slot="title">Title</h1><p>body</p>`,
+			want:      `<header><h1>Title</h1></header><main><p>body</p></main>`,
+		},
+		{
+			name:      "unknown slot name goes to the default slot",
+			component: `<header><slot name="title"></slot></header><main><slot></slot></main>`,
+			children:  `<p slot="footer">body</p>`,
+			want:      `<header></header><main><p slot="footer">body</p></main>`,
+		},
+		{
+			name:      "fallback content",
+			component: `<header><slot name="title"><h1>Untitled</h1></slot></header><main><slot>empty</slot></main>`,
+			children:  ``,
+			want:      `<header><h1>Untitled</h1></header><main>empty</main>`,
+		},
+		{
+			name:      "fallback only for the slots without content",
+			component: `<header><slot name="title"><h1>Untitled</h1></slot></header><main><slot>empty</slot></main>`,
+			children:  `<p>body</p>`,
+			want:      `<header><h1>Untitled</h1></header><main><p>body</p></main>`,
+		},
+		{
+			name:      "repeated slot gets the content once",
+			component: `<slot></slot><hr/><slot></slot>`,
+			children:  `<p>body</p>`,
+			want:      `<p>body</p><hr/>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := parseChildren(t, tt.component)
+			element := parseChildren(t, tt.children)
+
+			fillSlots(component, element)
+
+			var b bytes.Buffer
+			for n := component.FirstChild; n != nil; n = n.NextSibling {
+				err := html.Render(&b, n)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("got %q, want %q", b.String(), tt.want)
+			}
+
+			if element.FirstChild != nil {
+				t.Errorf("children left in the element")
+			}
+		})
+	}
+}
+
+func TestRecursivelyReplace(t *testing.T) {
+	tests := []struct {
+		name       string
+		components map[string]string
+		maxDepth   int
+		body       string
+		want       string
+		wantErr    string
+	}{
+		{
+			name: "nested components",
+			components: map[string]string{
+				"Page":   `<main><Header /></main>`,
+				"Header": `<header>top</header>`,
+			},
+			body: `<Page />`,
+			want: `<main><header>top</header></main>`,
+		},
+		{
+			name: "component used in the children of another",
+			components: map[string]string{
+				"Card":    `<div class="card"><slot></slot></div>`,
+				"Heading": `<h2>{{ $props.text }}</h2>`,
+			},
+			body: `<Card><Heading text="hi" /></Card>`,
+			want: `<div class="card"><h2>hi</h2></div>`,
+		},
+		{
+			name: "same component twice is not a cycle",
+			components: map[string]string{
+				"Pair": `<Item /><Item />`,
+				"Item": `<span>x</span>`,
+			},
+			body: `<Pair />`,
+			want: `<span>x</span><span>x</span>`,
+		},
+		{
+			name: "component using itself",
+			components: map[string]string{
+				"Loop": `<div><Loop /></div>`,
+			},
+			body:    `<Loop />`,
+			wantErr: "component cycle: Loop -> Loop",
+		},
+		{
+			name: "cycle through another component",
+			components: map[string]string{
+				"A": `<B />`,
+				"B": `<A />`,
+			},
+			body:    `<A />`,
+			wantErr: "component cycle: A -> B -> A",
+		},
+		{
+			name: "nesting up to the max depth",
+			components: map[string]string{
+				"One":   `<Two />`,
+				"Two":   `<Three />`,
+				"Three": `<p>deep</p>`,
+			},
+			maxDepth: 3,
+			body:     `<One />`,
+			want:     `<p>deep</p>`,
+		},
+		{
+			name: "nesting deeper than the max depth",
+			components: map[string]string{
+				"One":   `<Two />`,
+				"Two":   `<Three />`,
+				"Three": `<p>deep</p>`,
+			},
+			maxDepth: 2,
+			body:     `<One />`,
+			wantErr:  "components nested deeper than 2: One -> Two -> Three",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testServer(t, tt.components)
+			if tt.maxDepth > 0 {
+				s.Config.Components.MaxDepth = tt.maxDepth
+			}
+
+			got, err := renderBody(t, s, tt.body)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
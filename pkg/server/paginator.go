@@ -0,0 +1,89 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+// split pages into pagers of pageSize pages, the first pager is written at
+// sitepath and the rest at <sitepath>/page/<n>/. A pageSize of 0 keeps every
+// page on a single pager, an empty list still gets one (empty) pager.
+func paginate(pages []*parser.Meta, pageSize int, sitepath string) []*Pager {
+	if pageSize <= 0 || len(pages) == 0 {
+		pageSize = max(len(pages), 1)
+	}
+
+	pagers := make([]*Pager, 0, len(pages)/pageSize+1)
+
+	for start := 0; start == 0 || start < len(pages); start += pageSize {
+		number := len(pagers) + 1
+
+		pagers = append(pagers, &Pager{
+			Number:   number,
+			Sitepath: pagerSitepath(sitepath, number),
+			Pages:    pages[start:min(start+pageSize, len(pages))],
+		})
+	}
+
+	return pagers
+}
+
+func pagerSitepath(sitepath string, number int) string {
+	if number == 1 {
+		return sitepath
+	}
+
+	return fmt.Sprintf("%s/page/%d/", strings.TrimSuffix(sitepath, "/"), number)
+}
+
+// values of a pager exposed to templates as {{ $paginator.* }}
+func pagerData(pagers []*Pager, pager *Pager, pageSize int) map[string]any {
+	pages := make([]map[string]any, len(pager.Pages))
+	for i, page := range pager.Pages {
+		pages[i] = pageData(page)
+	}
+
+	totalItems := 0
+	for _, p := range pagers {
+		totalItems += len(p.Pages)
+	}
+
+	data := map[string]any{
+		"pages":      pages,
+		"pageNumber": pager.Number,
+		"pageSize":   pageSize,
+		"totalPages": len(pagers),
+		"totalItems": totalItems,
+		"sitepath":   pager.Sitepath,
+		"first":      pagers[0].Sitepath,
+		"last":       pagers[len(pagers)-1].Sitepath,
+		"hasPrev":    pager.Number > 1,
+		"hasNext":    pager.Number < len(pagers),
+		"prev":       "",
+		"next":       "",
+	}
+
+	if pager.Number > 1 {
+		data["prev"] = pagers[pager.Number-2].Sitepath
+	}
+
+	if pager.Number < len(pagers) {
+		data["next"] = pagers[pager.Number].Sitepath
+	}
+
+	return data
+}
+
+// page size of a list page: `pageSize` in its frontmatter, otherwise
+// pagination.pageSize from the config
+func (s *Server) pageSize(page *parser.Meta) int {
+	if page != nil {
+		if size, ok := frontmatterNumber(page, "pageSize"); ok {
+			return int(size)
+		}
+	}
+
+	return s.Config.Pagination.PageSize
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+func testPages(count int) []*parser.Meta {
+	pages := make([]*parser.Meta, count)
+	for i := range pages {
+		pages[i] = &parser.Meta{
+			Title:       fmt.Sprintf("page %d", i+1),
+			Frontmatter: parser.NewFrontmatter(),
+		}
+	}
+
+	return pages
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     int
+		pageSize  int
+		sitepath  string
+		wantSizes []int
+		wantPaths []string
+	}{
+		{
+			name:      "empty list gets one empty pager",
+			pages:     0,
+			pageSize:  10,
+			sitepath:  "/blog/",
+			wantSizes: []int{0},
+			wantPaths: []string{"/blog/"},
+		},
+		{
+			name:      "empty list without a page size",
+			pages:     0,
+			pageSize:  0,
+			sitepath:  "/blog/",
+			wantSizes: []int{0},
+			wantPaths: []string{"/blog/"},
+		},
+		{
+			name:      "page size 0 keeps everything on one pager",
+			pages:     5,
+			pageSize:  0,
+			sitepath:  "/blog/",
+			wantSizes: []int{5},
+			wantPaths: []string{"/blog/"},
+		},
+		{
+			name:      "negative page size keeps everything on one pager",
+			pages:     3,
+			pageSize:  -1,
+			sitepath:  "/blog/",
+			wantSizes: []int{3},
+			wantPaths: []string{"/blog/"},
+		},
+		{
+			name:      "fewer pages than the page size",
+			pages:     2,
+			pageSize:  10,
+			sitepath:  "/blog/",
+			wantSizes: []int{2},
+			wantPaths: []string{"/blog/"},
+		},
+		{
+			name:      "exactly one full pager",
+			pages:     3,
+			pageSize:  3,
+			sitepath:  "/blog/",
+			wantSizes: []int{3},
+			wantPaths: []string{"/blog/"},
+		},
+		{
+			name:      "last pager holds the rest",
+			pages:     7,
+			pageSize:  3,
+			sitepath:  "/blog/",
+			wantSizes: []int{3, 3, 1},
+			wantPaths: []string{"/blog/", "/blog/page/2/", "/blog/page/3/"},
+		},
+		{
+			name:      "full pagers only",
+			pages:     4,
+			pageSize:  2,
+			sitepath:  "/tags/go",
+			wantSizes: []int{2, 2},
+			wantPaths: []string{"/tags/go", "/tags/go/page/2/"},
+		},
+		{
+			name:      "page size of one",
+			pages:     2,
+			pageSize:  1,
+			sitepath:  "/",
+			wantSizes: []int{1, 1},
+			wantPaths: []string{"/", "/page/2/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := testPages(tt.pages)
+			pagers := paginate(pages, tt.pageSize, tt.sitepath)
+
+			if len(pagers) != len(tt.wantSizes) {
+				t.Fatalf("got %d pagers, want %d", len(pagers), len(tt.wantSizes))
+			}
+
+			seen := 0
+			for i, pager := range pagers {
+				if pager.Number != i+1 {
+					t.Errorf("pager %d: got number %d", i, pager.Number)
+				}
+
+				if len(pager.Pages) != tt.wantSizes[i] {
+					t.Errorf("pager %d: got %d pages, want %d", i, len(pager.Pages), tt.wantSizes[i])
+				}
+
+				if pager.Sitepath != tt.wantPaths[i] {
+					t.Errorf("pager %d: got sitepath %q, want %q", i, pager.Sitepath, tt.wantPaths[i])
+				}
+
+				// pages keep their order across pagers
+				for _, page := range pager.Pages {
+					if page != pages[seen] {
+						t.Errorf("pager %d: got %s, want %s", i, page.Title, pages[seen].Title)
+					}
+					seen++
+				}
+			}
+
+			if seen != len(pages) {
+				t.Errorf("got %d pages across pagers, want %d", seen, len(pages))
+			}
+		})
+	}
+}
+
+func TestPagerData(t *testing.T) {
+	pagers := paginate(testPages(5), 2, "/blog/")
+
+	tests := []struct {
+		name     string
+		pager    *Pager
+		wantPrev string
+		wantNext string
+		hasPrev  bool
+		hasNext  bool
+	}{
+		{
+			name:     "first",
+			pager:    pagers[0],
+			wantNext: "/blog/page/2/",
+			hasNext:  true,
+		},
+		{
+			name:     "middle",
+			pager:    pagers[1],
+			wantPrev: "/blog/",
+			wantNext: "/blog/page/3/",
+			hasPrev:  true,
+			hasNext:  true,
+		},
+		{
+			name:     "last",
+			pager:    pagers[2],
+			wantPrev: "/blog/page/2/",
+			hasPrev:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := pagerData(pagers, tt.pager, 2)
+
+			want := map[string]any{
+				"prev":       tt.wantPrev,
+				"next":       tt.wantNext,
+				"hasPrev":    tt.hasPrev,
+				"hasNext":    tt.hasNext,
+				"first":      "/blog/",
+				"last":       "/blog/page/3/",
+				"totalPages": 3,
+				"totalItems": 5,
+				"pageNumber": tt.pager.Number,
+				"pageSize":   2,
+			}
+
+			for key, value := range want {
+				if data[key] != value {
+					t.Errorf("%s: got %v, want %v", key, data[key], value)
+				}
+			}
+
+			if pages := data["pages"].([]map[string]any); len(pages) != len(tt.pager.Pages) {
+				t.Errorf("got %d pages, want %d", len(pages), len(tt.pager.Pages))
+			}
+		})
+	}
+}
+
+func TestPagerDataEmpty(t *testing.T) {
+	pagers := paginate(nil, 10, "/blog/")
+	data := pagerData(pagers, pagers[0], 10)
+
+	want := map[string]any{
+		"prev":       "",
+		"next":       "",
+		"hasPrev":    false,
+		"hasNext":    false,
+		"first":      "/blog/",
+		"last":       "/blog/",
+		"totalPages": 1,
+		"totalItems": 0,
+		"pageNumber": 1,
+	}
+
+	for key, value := range want {
+		if data[key] != value {
+			t.Errorf("%s: got %v, want %v", key, data[key], value)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+// a page left out of the build and why
+type exclusion struct {
+	Path   string
+	Reason string
+}
+
+// whether a page is rendered, reason says why it is not:
+//   - `publish: false` pages are never rendered, pages without `publish` are
+//     only rendered with publishByDefault in the config
+//   - `draft: true` pages are rendered with --drafts
+//   - pages published in the future are scheduled, rendered with --future
+//   - pages past their `expiryDate` are never rendered
+func (s *Server) publishStatus(markdownMeta *parser.Meta) (bool, string) {
+	frontmatter := markdownMeta.Frontmatter
+
+	if publish, ok := frontmatter.Get("publish"); ok {
+		published, ok := frontmatterBool(publish)
+		if !ok {
+			return false, fmt.Sprintf("publish %v is not true or false", publish)
+		}
+
+		if !published {
+			return false, "publish is false"
+		}
+	} else if !s.Config.PublishByDefault {
+		return false, "publish is not set"
+	}
+
+	if draft, ok := frontmatter.Get("draft"); ok {
+		isDraft, ok := frontmatterBool(draft)
+		if !ok {
+			return false, fmt.Sprintf("draft %v is not true or false", draft)
+		}
+
+		if isDraft && !s.Config.BuildDrafts {
+			return false, "draft"
+		}
+	}
+
+	now := time.Now()
+
+	if markdownMeta.PublishDate.After(now) && !s.Config.BuildFuture {
+		return false, fmt.Sprintf("scheduled for %s", markdownMeta.PublishDate.Format(time.DateOnly))
+	}
+
+	if expiry, ok := frontmatterTime(markdownMeta, "expiryDate"); ok && !expiry.After(now) {
+		return false, fmt.Sprintf("expired on %s", expiry.Format(time.DateOnly))
+	}
+
+	return true, ""
+}
+
+func (s *Server) isPublished(markdownMeta *parser.Meta) bool {
+	published, _ := s.publishStatus(markdownMeta)
+
+	return published
+}
+
+// booleans written as yaml booleans or as strings, ie: "true"
+func frontmatterBool(value any) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+
+	return false, false
+}
+
+// path of a file inside the source folder, for messages
+func (s *Server) relativeToSrc(path string) string {
+	rel, err := filepath.Rel(s.SrcPath, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+// log the pages left out of a build
+func logExclusions(exclusions []exclusion) {
+	log := utils.NewLogger()
+
+	if len(exclusions) == 0 {
+		return
+	}
+
+	sort.Slice(exclusions, func(i, j int) bool {
+		return exclusions[i].Path < exclusions[j].Path
+	})
+
+	log.Infow("Pages excluded from the build", "count", len(exclusions))
+
+	for _, e := range exclusions {
+		log.Infow("Excluded", "page", filepath.ToSlash(e.Path), "reason", e.Reason)
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+func TestPublishStatus(t *testing.T) {
+	past := time.Now().AddDate(0, 0, -1)
+	future := time.Now().AddDate(0, 0, 1)
+
+	tests := []struct {
+		name        string
+		config      models.Config
+		frontmatter map[string]any
+		publishDate time.Time
+		want        bool
+		wantReason  string
+	}{
+		{
+			name:        "published",
+			frontmatter: map[string]any{"publish": true},
+			want:        true,
+		},
+		{
+			name:        "publish as a string",
+			frontmatter: map[string]any{"publish": "true"},
+			want:        true,
+		},
+		{
+			name:        "publish false",
+			frontmatter: map[string]any{"publish": false},
+			wantReason:  "publish is false",
+		},
+		{
+			name:        "publish false with publishByDefault",
+			config:      models.Config{PublishByDefault: true},
+			frontmatter: map[string]any{"publish": false},
+			wantReason:  "publish is false",
+		},
+		{
+			name:        "publish not a bool",
+			frontmatter: map[string]any{"publish": "yes please"},
+			wantReason:  "publish yes please is not true or false",
+		},
+		{
+			name:        "publish not set",
+			frontmatter: map[string]any{},
+			wantReason:  "publish is not set",
+		},
+		{
+			name:        "publish not set with publishByDefault",
+			config:      models.Config{PublishByDefault: true},
+			frontmatter: map[string]any{},
+			want:        true,
+		},
+		{
+			name:        "draft",
+			frontmatter: map[string]any{"publish": true, "draft": true},
+			wantReason:  "draft",
+		},
+		{
+			name:        "draft with --drafts",
+			config:      models.Config{BuildDrafts: true},
+			frontmatter: map[string]any{"publish": true, "draft": true},
+			want:        true,
+		},
+		{
+			name:        "draft not a bool",
+			frontmatter: map[string]any{"publish": true, "draft": 1},
+			wantReason:  "draft 1 is not true or false",
+		},
+		{
+			name:        "published in the past",
+			frontmatter: map[string]any{"publish": true},
+			publishDate: past,
+			want:        true,
+		},
+		{
+			name:        "scheduled",
+			frontmatter: map[string]any{"publish": true},
+			publishDate: future,
+			wantReason:  "scheduled for " + future.Format(time.DateOnly),
+		},
+		{
+			name:        "scheduled with --future",
+			config:      models.Config{BuildFuture: true},
+			frontmatter: map[string]any{"publish": true},
+			publishDate: future,
+			want:        true,
+		},
+		{
+			name:        "expired",
+			frontmatter: map[string]any{"publish": true, "expiryDate": past.Format(time.RFC3339)},
+			wantReason:  "expired on " + past.Format(time.DateOnly),
+		},
+		{
+			name:        "expires later",
+			frontmatter: map[string]any{"publish": true, "expiryDate": future.Format(time.RFC3339)},
+			want:        true,
+		},
+		{
+			name:        "expired even with --drafts and --future",
+			config:      models.Config{BuildDrafts: true, BuildFuture: true},
+			frontmatter: map[string]any{"publish": true, "expiryDate": past.Format(time.RFC3339)},
+			wantReason:  "expired on " + past.Format(time.DateOnly),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewServer(&tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			frontmatter := parser.NewFrontmatter()
+			for key, value := range tt.frontmatter {
+				frontmatter.Set(key, value)
+			}
+
+			got, reason := s.publishStatus(&parser.Meta{
+				Frontmatter: frontmatter,
+				PublishDate: tt.publishDate,
+			})
+
+			if got != tt.want || reason != tt.wantReason {
+				t.Errorf("got %v %q, want %v %q", got, reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
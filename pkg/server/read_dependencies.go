@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,6 +25,30 @@ func (s *Server) readDependencies() error {
 	return nil
 }
 
+// folders to read a dependency folder (templates, components, assets) from,
+// the theme comes first so that the files of the site override it. Folders
+// missing from the theme or the site are skipped, a site using a theme only
+// needs the folders it overrides.
+func (s *Server) layerPaths(folder string) []string {
+	paths := make([]string, 0, 2)
+
+	roots := []string{s.SrcPath}
+	if s.ThemePath != "" {
+		roots = []string{s.ThemePath, s.SrcPath}
+	}
+
+	for _, root := range roots {
+		layerFolder := filepath.Join(root, folder)
+
+		exists, err := utils.PathExists(layerFolder)
+		if err == nil && exists {
+			paths = append(paths, layerFolder)
+		}
+	}
+
+	return paths
+}
+
 func (s *Server) readComponents() error {
 	log := utils.NewLogger()
 
@@ -33,45 +58,56 @@ func (s *Server) readComponents() error {
 		log.Debugw("Time taken to read components", "time", time.Since(start))
 	}()
 
-	// collect components from the directory
-	err := filepath.WalkDir(filepath.Join(s.SrcPath, "components"), func(
-		path string,
-		info os.DirEntry,
-		err error,
-	) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
+	// collect components from the theme and the site
+	for _, componentsPath := range s.layerPaths("components") {
+		err := filepath.WalkDir(componentsPath, func(
+			path string,
+			info os.DirEntry,
+			err error,
+		) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			// strip the .html extension
+			componentName := strings.ToLower(
+				strings.TrimSuffix(info.Name(), ".html"),
+			)
+
+			f := parser.NewFile(path, parser.FILE_TYPE_COMPONENT)
+			err = f.ReadFile()
+			if err != nil {
+				return err
+			}
+
+			// the frontmatter of a component declares the defaults of its props
+			frontmatter, body, err := parser.SplitFrontmatter(f.Body)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			f.Body = body
+
+			s.ComponentsMD.Set(componentName, &parser.Meta{
+				Title:       componentName,
+				Description: "",
+				F:           f,
+				Tags:        make([]string, 0),
+				Frontmatter: frontmatter,
+			})
+
+			log.Infow("Component File: ", "componentName", componentName, "path", path)
 
-		// strip the .html extension
-		componentName := strings.ToLower(
-			strings.TrimSuffix(info.Name(), ".html"),
-		)
+			return nil
+		})
 
-		f := parser.NewFile(path, parser.FILE_TYPE_COMPONENT)
-		err = f.ReadFile()
 		if err != nil {
+			log.Errorw("Error reading components", "error", err)
 			return err
 		}
-
-		s.ComponentsMD.Set(componentName, &parser.Meta{
-			Title:       componentName,
-			Description: "",
-			F:           f,
-			Tags:        make([]string, 0),
-		})
-
-		log.Infow("Component File: ", "componentName", componentName)
-
-		return nil
-	})
-
-	if err != nil {
-		log.Errorw("Error reading components", "error", err)
-		return err
 	}
 
 	return nil
@@ -86,38 +122,57 @@ func (s *Server) readTemplates() error {
 		log.Debugw("Time taken to read templates", "time", time.Since(start))
 	}()
 
-	// read templates
-	err := filepath.Walk(filepath.Join(s.SrcPath, "templates"), func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	// read templates from the theme and the site, templates are keyed by
+	// their path in the site so that lookups do not care where they came from
+	siteTemplatesPath := filepath.Join(s.SrcPath, "templates")
 
-		if info.IsDir() {
-			return nil
-		}
+	for _, templatesPath := range s.layerPaths("templates") {
+		err := filepath.Walk(templatesPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
 
-		log.Infow("Template File: ", "path", path)
+			if info.IsDir() {
+				return nil
+			}
 
-		f := parser.NewFile(path, parser.FILE_TYPE_TEMPLATE)
+			log.Infow("Template File: ", "path", path)
 
-		s.TemplateMD.Set(f.Path, &parser.Meta{
-			Title:       path,
-			Description: "",
-			F:           f,
-			Tags:        make([]string, 0),
-		})
+			relativePath, err := filepath.Rel(templatesPath, path)
+			if err != nil {
+				return err
+			}
+
+			f := parser.NewFile(path, parser.FILE_TYPE_TEMPLATE)
 
-		err = f.ReadFile()
+			err = f.ReadFile()
 
+			if err != nil {
+				return err
+			}
+
+			// the frontmatter of a template names the layout it extends
+			frontmatter, body, err := parser.SplitFrontmatter(f.Body)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			f.Body = body
+
+			s.TemplateMD.Set(filepath.Join(siteTemplatesPath, relativePath), &parser.Meta{
+				Title:       path,
+				Description: "",
+				F:           f,
+				Tags:        make([]string, 0),
+				Frontmatter: frontmatter,
+			})
+
+			return nil
+		})
 		if err != nil {
+			log.Errorw("Error reading templates", "error", err)
 			return err
 		}
-
-		return nil
-	})
-	if err != nil {
-		log.Errorw("Error reading templates", "error", err)
-		return err
 	}
 
 	return nil
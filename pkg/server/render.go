@@ -3,11 +3,17 @@ package server
 import (
 	"errors"
 	"fmt"
+	"html/template"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
+
+	gconfig "github.com/shreyaskaundinya/garlic/pkg/config"
 	"github.com/shreyaskaundinya/garlic/pkg/parser"
 	"github.com/shreyaskaundinya/garlic/pkg/utils"
 )
@@ -15,6 +21,14 @@ import (
 func (s *Server) runBeforeRenderProcess(event *RenderEvent) error {
 	log := utils.NewLogger()
 
+	if event.RenderAll && s.Config.Output.Clean {
+		err := s.cleanDest()
+		if err != nil {
+			log.Errorw("Error cleaning destination", "error", err)
+			return err
+		}
+	}
+
 	if event.ProcessAssets {
 		err := s.readAndCopyAssets()
 		if err != nil {
@@ -31,89 +45,166 @@ func (s *Server) runBeforeRenderProcess(event *RenderEvent) error {
 		}
 	}
 
+	if event.ProcessData {
+		err := s.readData()
+		if err != nil {
+			log.Errorw("Error reading data", "error", err)
+			return err
+		}
+	}
+
+	// the git log is read once per full build, the dates only change with
+	// new commits
+	if event.RenderAll {
+		s.readGitDates()
+	}
+
 	return nil
 }
 
-func (s *Server) readAndCopyAssets() error {
+// remove everything inside the destination folder, keeping the folder itself
+func (s *Server) cleanDest() error {
 	log := utils.NewLogger()
 
-	assetsSrcPath := filepath.Join(s.SrcPath, "assets")
-	assetsDestPath := filepath.Join(s.DestPath, "assets")
+	// the config is validated, this guards against anything slipping through
+	if utils.IsWithin(s.DestPath, s.SrcPath) || utils.IsWithin(s.SrcPath, s.DestPath) {
+		return fmt.Errorf("refusing to clean %s, it overlaps the source folder %s", s.DestPath, s.SrcPath)
+	}
 
-	// Check if assets directory exists
-	if _, err := os.Stat(assetsSrcPath); os.IsNotExist(err) {
-		log.Infow("Assets directory does not exist, skipping asset copy", "path", assetsSrcPath)
-		return nil
+	configPath, err := gconfig.FindConfigFile(s.DestPath)
+	if err != nil {
+		return err
+	}
+
+	if configPath != "" {
+		return fmt.Errorf("refusing to clean %s, it holds the config file %s", s.DestPath, configPath)
+	}
+
+	entries, err := os.ReadDir(s.DestPath)
+	if err != nil {
+		return err
 	}
 
-	// copy assets from source to destination
-	err := filepath.WalkDir(assetsSrcPath, func(srcPath string, info os.DirEntry, err error) error {
+	for _, entry := range entries {
+		err = os.RemoveAll(filepath.Join(s.DestPath, entry.Name()))
 		if err != nil {
-			return fmt.Errorf("error walking directory: %w", err)
+			return err
 		}
+	}
 
-		// Get relative path from assets source directory
-		relPath, err := filepath.Rel(assetsSrcPath, srcPath)
-		if err != nil {
-			return fmt.Errorf("error getting relative path: %w", err)
+	log.Infow("Cleaned destination folder", "path", s.DestPath)
+	return nil
+}
+
+func (s *Server) readAndCopyAssets() error {
+	log := utils.NewLogger()
+
+	assetsDestPath := filepath.Join(s.DestPath, "assets")
+
+	// copy the theme assets first so that the assets of the site win
+	for _, assetsSrcPath := range s.layerPaths("assets") {
+		// Check if assets directory exists
+		if _, err := os.Stat(assetsSrcPath); os.IsNotExist(err) {
+			log.Infow("Assets directory does not exist, skipping asset copy", "path", assetsSrcPath)
+			continue
 		}
 
-		destPath := filepath.Join(assetsDestPath, relPath)
+		// copy assets from source to destination
+		err := filepath.WalkDir(assetsSrcPath, func(srcPath string, info os.DirEntry, err error) error {
+			if err != nil {
+				return fmt.Errorf("error walking directory: %w", err)
+			}
 
-		if info.IsDir() {
-			// Create directory in destination
-			err = os.MkdirAll(destPath, os.ModePerm)
+			// Get relative path from assets source directory
+			relPath, err := filepath.Rel(assetsSrcPath, srcPath)
 			if err != nil {
-				return fmt.Errorf("error creating directory: %w", err)
+				return fmt.Errorf("error getting relative path: %w", err)
 			}
-			return nil
-		}
 
-		// Read source file content
-		content, err := os.ReadFile(srcPath)
-		if err != nil {
-			return fmt.Errorf("error reading file: %w", err)
-		}
+			destPath := filepath.Join(assetsDestPath, relPath)
 
-		// Create destination directory if it doesn't exist
-		err = os.MkdirAll(filepath.Dir(destPath), os.ModePerm)
-		if err != nil {
-			return fmt.Errorf("error creating directory: %w", err)
-		}
+			if info.IsDir() {
+				// Create directory in destination
+				err = os.MkdirAll(destPath, os.ModePerm)
+				if err != nil {
+					return fmt.Errorf("error creating directory: %w", err)
+				}
+				return nil
+			}
 
-		// Remove existing file if it exists to avoid permission issues
-		if _, err := os.Stat(destPath); err == nil {
-			err = os.Remove(destPath)
+			// Read source file content
+			content, err := os.ReadFile(srcPath)
 			if err != nil {
-				return fmt.Errorf("error removing existing file: %w", err)
+				return fmt.Errorf("error reading file: %w", err)
+			}
+
+			// Create destination directory if it doesn't exist
+			err = os.MkdirAll(filepath.Dir(destPath), os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("error creating directory: %w", err)
 			}
-		}
 
-		// Write file to destination
-		err = os.WriteFile(destPath, content, 0644)
+			// Remove existing file if it exists to avoid permission issues
+			if _, err := os.Stat(destPath); err == nil {
+				err = os.Remove(destPath)
+				if err != nil {
+					return fmt.Errorf("error removing existing file: %w", err)
+				}
+			}
+
+			// Write file to destination
+			err = os.WriteFile(destPath, content, 0644)
+			if err != nil {
+				return fmt.Errorf("error writing file: %w", err)
+			}
+
+			log.Debugw("Copied asset file", "src", srcPath, "dest", destPath)
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("error writing file: %w", err)
+			log.Errorw("Error copying assets", "error", err)
+			return err
 		}
 
-		log.Debugw("Copied asset file", "src", srcPath, "dest", destPath)
-		return nil
-	})
-	if err != nil {
-		log.Errorw("Error copying assets", "error", err)
-		return err
+		log.Infow("Successfully copied all assets", "src", assetsSrcPath, "dest", assetsDestPath)
 	}
 
-	log.Infow("Successfully copied all assets", "src", assetsSrcPath, "dest", assetsDestPath)
 	return nil
 }
 
 func (s *Server) runAfterRenderProcess(event *RenderEvent) error {
 	log := utils.NewLogger()
 
-	if event.ProcessTags {
-		err := s.processTags()
+	if event.ProcessSections {
+		err := s.processSections()
 		if err != nil {
-			log.Errorw("Error processing tags", "error", err)
+			log.Errorw("Error processing sections", "error", err)
+			return err
+		}
+	}
+
+	if event.ProcessTaxonomies {
+		err := s.processTaxonomies()
+		if err != nil {
+			log.Errorw("Error processing taxonomies", "error", err)
+			return err
+		}
+
+		err = s.processFeeds()
+		if err != nil {
+			log.Errorw("Error processing feeds", "error", err)
+			return err
+		}
+
+		err = s.processSitemap()
+		if err != nil {
+			log.Errorw("Error processing sitemap", "error", err)
+			return err
+		}
+
+		err = s.processSearchIndex()
+		if err != nil {
+			log.Errorw("Error processing search index", "error", err)
 			return err
 		}
 	}
@@ -133,35 +224,41 @@ func (s *Server) setupMarkdown(path string) (*parser.Meta, error) {
 		return nil, err
 	}
 
-	frontmatter := s.Parser.Parse(f)
-	if frontmatter == nil {
-		log.Errorw("Error parsing frontmatter", "path", path)
-		return nil, errors.New("error parsing frontmatter")
+	frontmatter, err := s.Parser.Parse(f)
+	if err != nil {
+		log.Errorw("Error parsing frontmatter", "path", path, "error", err)
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// frontmatter not matching the schema only fails the build when strict
+	err = s.validateFrontmatter(path, frontmatter)
+	if err != nil {
+		if s.Config.Strict {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		log.Warnw("Frontmatter does not match the schema", "path", path, "error", err)
 	}
 
 	title, _ := frontmatter.Get("title")
 
 	description, _ := frontmatter.Get("description")
 
-	contentPath := strings.TrimPrefix(path, s.SrcPath)
-	pathSplits := strings.Split(contentPath, string(os.PathSeparator))
-
-	sitePath := filepath.Join(pathSplits[2:]...)
+	contentPath, err := filepath.Rel(filepath.Join(s.SrcPath, "content"), path)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in the content folder: %w", path, err)
+	}
 
-	sitepath := strings.TrimPrefix(
-		strings.TrimSuffix(sitePath, filepath.Ext(sitePath)),
-		s.SrcPath,
-	)
+	sitepath := filepath.ToSlash(strings.TrimSuffix(contentPath, filepath.Ext(contentPath)))
 
-	// if sitepath ends with index, remove it
+	// if sitepath ends with index (or _index for sections), remove it
+	sitepath = strings.TrimSuffix(sitepath, "_index")
 	sitepath = strings.TrimSuffix(sitepath, "index")
 
 	sitepath = "/" + sitepath
 
-	log.Infow("[Sitepath]",
+	log.Debugw("[Sitepath]",
 		"contentPath", contentPath,
-		"pathSplits", pathSplits,
-		"sitePath", sitePath,
 		"sitepath", sitepath,
 	)
 
@@ -174,11 +271,41 @@ func (s *Server) setupMarkdown(path string) (*parser.Meta, error) {
 		Frontmatter: frontmatter,
 	}
 
+	s.setDates(markdownMeta)
+
 	s.MD.Set(f.Path, markdownMeta)
 
 	return markdownMeta, nil
 }
 
+// render the markdown of a page and inject it into its template, extra is
+// added to the template data (ie: $section and $paginator of list pages)
+func (s *Server) renderMarkdown(markdownMeta *parser.Meta, extra map[string]any) (string, error) {
+	html, err := s.renderContent(markdownMeta)
+	if err != nil {
+		return "", err
+	}
+
+	data := s.templateData(markdownMeta, template.HTML(html))
+
+	data["toc"], err = s.tocHTML(markdownMeta)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", markdownMeta.F.Path, err)
+	}
+
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	// inject html into template
+	content, err := s.injectHTML(markdownMeta, data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", markdownMeta.F.Path, err)
+	}
+
+	return content, nil
+}
+
 func (s *Server) processEvent(event *RenderEvent) {
 	log := utils.NewLogger()
 
@@ -189,14 +316,23 @@ func (s *Server) processEvent(event *RenderEvent) {
 	if event.RenderAll {
 		event.ProcessAssets = true
 		event.ProcessDependencies = true
-		event.ProcessTags = true
+		event.ProcessData = true
+		event.ProcessTaxonomies = true
+		event.ProcessSections = true
 		event.ProcessContent = true
 		return
 	}
 
 	// read the path of changed file
 	path := event.Event.Name
-	relativePath, err := filepath.Rel(s.SrcPath, path)
+
+	// files of the theme are treated like the files of the site they override
+	rootPath := s.SrcPath
+	if s.ThemePath != "" && strings.HasPrefix(path, s.ThemePath+string(os.PathSeparator)) {
+		rootPath = s.ThemePath
+	}
+
+	relativePath, err := filepath.Rel(rootPath, path)
 	if err != nil {
 		log.Errorw("Error getting relative path", "error", err)
 		event.RenderAll = true
@@ -209,24 +345,136 @@ func (s *Server) processEvent(event *RenderEvent) {
 		event.ProcessAssets = true
 	}
 
-	// if dependencies are changed, process dependencies and tags
+	// if dependencies are changed, process dependencies and taxonomies
 	if strings.HasPrefix(relativePath, "components") {
 		event.ProcessDependencies = true
 		event.ProcessContent = true
-		event.ProcessTags = true
+		event.ProcessTaxonomies = true
+		event.ProcessSections = true
 	}
 
 	if strings.HasPrefix(relativePath, "templates") {
 		event.ProcessDependencies = true
 		event.ProcessContent = true
-		event.ProcessTags = true
+		event.ProcessTaxonomies = true
+		event.ProcessSections = true
+	}
+
+	// only the pages reading $data are rendered again, see dependsOnData
+	if strings.HasPrefix(relativePath, DATA_FOLDER) {
+		event.ProcessData = true
+		event.ProcessContent = true
+		event.ProcessTaxonomies = true
+		event.ProcessSections = true
 	}
 
 	if strings.HasPrefix(relativePath, "content") {
+		// the walk of the content only sees the files that still exist
+		if event.Event.Has(fsnotify.Remove) || event.Event.Has(fsnotify.Rename) {
+			s.forgetContent(path)
+		}
+
 		event.ProcessDependencies = true
 		event.ProcessContent = true
-		event.ProcessTags = true
+		event.ProcessTaxonomies = true
+		event.ProcessSections = true
+	}
+}
+
+// drop the pages of a removed (or renamed) content file or folder, they would
+// otherwise still be listed in sections, taxonomies and feeds
+func (s *Server) forgetContent(path string) {
+	log := utils.NewLogger()
+
+	s.MD.Range(func(key string, _ *parser.Meta) bool {
+		if key == path || strings.HasPrefix(key, path+string(os.PathSeparator)) {
+			s.MD.Delete(key)
+			log.Infow("Removed page", "path", key)
+		}
+		return true
+	})
+}
+
+// Check parses every content file and renders it into its template without
+// writing anything to the destination folder, all problems are reported
+func (s *Server) Check() error {
+	log := utils.NewLogger()
+
+	err := s.readDependencies()
+	if err != nil {
+		return err
+	}
+
+	err = s.readData()
+	if err != nil {
+		return err
+	}
+
+	s.readGitDates()
+
+	errs := make([]error, 0)
+	exclusions := make([]exclusion, 0)
+	pages := 0
+
+	err = filepath.WalkDir(filepath.Join(s.SrcPath, "content"), func(path string, info os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		markdownMeta, err := s.setupMarkdown(path)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		published, reason := s.publishStatus(markdownMeta)
+		if !published {
+			exclusions = append(exclusions, exclusion{Path: s.relativeToSrc(path), Reason: reason})
+			return nil
+		}
+
+		// sections are checked once every page has been read
+		if isSectionIndex(path) {
+			return nil
+		}
+
+		_, err = s.renderMarkdown(markdownMeta, nil)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		pages++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, section := range s.sectionIndexes() {
+		rendered, err := s.renderSection(section)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		pages += len(rendered)
 	}
+
+	// warns about the terms that would be skipped in a build
+	for _, name := range slices.Sorted(maps.Keys(s.Config.Taxonomies)) {
+		s.taxonomyTerms(name, "/"+s.Config.Taxonomies[name].Path)
+	}
+
+	logExclusions(exclusions)
+
+	log.Infow("Checked content", "pages", pages, "errors", len(errs))
+
+	return errors.Join(errs...)
 }
 
 func (s *Server) render(event *RenderEvent) error {
@@ -246,15 +494,23 @@ func (s *Server) render(event *RenderEvent) error {
 	}()
 
 	if event.ProcessContent {
+		exclusions := make([]exclusion, 0)
+
 		// currentDir := ""
 		// depth := 1
 		// read blogs
-		err = filepath.WalkDir(filepath.Join(s.SrcPath, "content"), func(path string, info os.DirEntry, err error) error {
+		contentPath := filepath.Join(s.SrcPath, "content")
+
+		err = filepath.WalkDir(contentPath, func(path string, info os.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
 
-			relativePath := strings.Split(path, s.SrcPath)[1]
+			// the path of the file inside the content folder
+			relativePath, err := filepath.Rel(contentPath, path)
+			if err != nil {
+				return err
+			}
 
 			// depth := strings.Count(relativePath, "\\")
 			// log.Infow(relativePath, "depth", depth)
@@ -271,44 +527,27 @@ func (s *Server) render(event *RenderEvent) error {
 				return err
 			}
 
-			publishIf, ok := markdownMeta.Frontmatter.Get("publish")
-
-			if !ok {
-				log.Infow("%s missing publish attribute in metadata (front matter)", "name", info.Name())
+			published, reason := s.publishStatus(markdownMeta)
+			if !published {
+				exclusions = append(exclusions, exclusion{Path: s.relativeToSrc(path), Reason: reason})
 				return nil
 			}
 
-			publish := publishIf.(bool)
-
-			if !publish {
+			// list pages of sections are rendered after every page is read
+			if isSectionIndex(path) {
 				return nil
 			}
 
-			html, err := s.Parser.Render(markdownMeta.F)
-
-			if err != nil {
-				return err
+			// when only data changed, pages not reading it are up to date
+			if event.ProcessData && !event.ProcessDependencies && !s.dependsOnData(markdownMeta) {
+				return nil
 			}
 
-			// inject html into template
-			content, err := s.injectHTML(markdownMeta, html)
+			content, err := s.renderMarkdown(markdownMeta, nil)
 			if err != nil {
 				return err
 			}
 
-			log.Infow("[debug] relativePath", "relativePath", relativePath)
-
-			// remove content/ from the relative path
-			splits := strings.Split(relativePath, string(os.PathSeparator))
-
-			if len(splits) == 0 {
-				return nil
-			}
-
-			relativePath = filepath.Join(splits[2:]...)
-
-			log.Infow("[debug] relativePath", "relativePath", relativePath, "splits", splits)
-
 			fileName := utils.FileNameWithoutExtension(
 				strings.TrimPrefix(
 					relativePath, filepath.Dir(relativePath),
@@ -360,6 +599,8 @@ func (s *Server) render(event *RenderEvent) error {
 			log.Errorw("Error rendering", "error", err)
 			return err
 		}
+
+		logExclusions(exclusions)
 	}
 
 	// run after render process
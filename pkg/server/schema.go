@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/shreyaskaundinya/garlic/models"
+	gconfig "github.com/shreyaskaundinya/garlic/pkg/config"
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+// fields of the schema a page is checked against: the fields of every page
+// with the fields of its closest section on top, _index.md pages belong to
+// the section above theirs
+func (s *Server) schemaFields(path string) map[string]models.FieldSchema {
+	fields := maps.Clone(s.Config.Schema.Fields)
+	if fields == nil {
+		fields = map[string]models.FieldSchema{}
+	}
+
+	contentPath, err := filepath.Rel(filepath.Join(s.SrcPath, "content"), path)
+	if err != nil {
+		return fields
+	}
+
+	section := filepath.Dir(contentPath)
+
+	// the list page of a section is not one of its pages
+	if isSectionIndex(path) {
+		section = filepath.Dir(section)
+	}
+
+	for ; section != "."; section = filepath.Dir(section) {
+		sectionFields, ok := s.Config.Schema.Sections[filepath.ToSlash(section)]
+		if ok {
+			maps.Copy(fields, sectionFields)
+			break
+		}
+	}
+
+	return fields
+}
+
+// check the frontmatter of a page against the schema, missing fields with a
+// default get it. Every problem is reported.
+func (s *Server) validateFrontmatter(path string, frontmatter *parser.Frontmatter) error {
+	fields := s.schemaFields(path)
+
+	errs := make([]error, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		field := fields[name]
+
+		value, ok := frontmatter.Get(name)
+		if !ok || value == nil {
+			if field.Default != nil {
+				frontmatter.Set(name, field.Default)
+				continue
+			}
+
+			if field.Required {
+				errs = append(errs, fmt.Errorf("field %s is required", name))
+			}
+			continue
+		}
+
+		err := gconfig.CheckField(field, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
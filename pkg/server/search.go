@@ -0,0 +1,256 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const (
+	SEARCH_INDEX_FILE  = "search.json"
+	SEARCH_SHARDS_PATH = "search"
+)
+
+// the list of shards written in search/index.json when the index is sharded
+type searchShards struct {
+	Pages  int      `json:"pages"`
+	Fields []string `json:"fields"`
+	Shards []string `json:"shards"`
+}
+
+// the search index is only written when enabled in the config
+func (s *Server) searchEnabled() bool {
+	return s.Config.Search.Enabled
+}
+
+// write the search index of the published pages, as a single search.json or
+// as shards of shardSize pages listed in search/index.json
+func (s *Server) processSearchIndex() error {
+	log := utils.NewLogger()
+
+	if !s.searchEnabled() {
+		return nil
+	}
+
+	pages := make([]*parser.Meta, 0)
+	s.MD.Range(func(path string, page *parser.Meta) bool {
+		if !isSectionIndex(path) && s.isPublished(page) && inSearch(page) {
+			pages = append(pages, page)
+		}
+		return true
+	})
+
+	// keep the index stable across builds
+	slices.SortFunc(pages, func(a, b *parser.Meta) int {
+		return strings.Compare(a.Sitepath, b.Sitepath)
+	})
+
+	entries := make([]map[string]any, len(pages))
+	for i, page := range pages {
+		entries[i] = s.searchEntry(page)
+	}
+
+	shardSize := s.Config.Search.ShardSize
+
+	// the index of a previous build written the other way
+	err := s.removeSearchIndex(shardSize == 0)
+	if err != nil {
+		return err
+	}
+
+	if shardSize == 0 {
+		err := writeJSON(filepath.Join(s.DestPath, SEARCH_INDEX_FILE), entries)
+		if err != nil {
+			return err
+		}
+
+		log.Infow("Processed search index", "pages", len(entries))
+
+		return nil
+	}
+
+	shardsPath := filepath.Join(s.DestPath, SEARCH_SHARDS_PATH)
+
+	err = os.MkdirAll(shardsPath, 0755)
+	if err != nil {
+		return err
+	}
+
+	// shards of a previous build with more pages
+	err = removeSearchShards(shardsPath)
+	if err != nil {
+		return err
+	}
+
+	shards := searchShards{
+		Pages:  len(entries),
+		Fields: s.Config.Search.Fields,
+		Shards: make([]string, 0),
+	}
+
+	number := 1
+	for shard := range slices.Chunk(entries, shardSize) {
+		name := fmt.Sprintf("%d.json", number)
+
+		err = writeJSON(filepath.Join(shardsPath, name), shard)
+		if err != nil {
+			return err
+		}
+
+		shards.Shards = append(shards.Shards, s.absoluteURL(path.Join("/", SEARCH_SHARDS_PATH, name)))
+		number++
+	}
+
+	err = writeJSON(filepath.Join(shardsPath, "index.json"), shards)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("Processed search index", "pages", len(entries), "shards", len(shards.Shards))
+
+	return nil
+}
+
+// the fields of a page in the search index, fields that are not computed are
+// read from the frontmatter
+func (s *Server) searchEntry(page *parser.Meta) map[string]any {
+	entry := make(map[string]any, len(s.Config.Search.Fields))
+
+	var data map[string]any
+
+	for _, field := range s.Config.Search.Fields {
+		switch field {
+		case "title":
+			entry[field] = page.Title
+		case "sitepath":
+			entry[field] = page.Sitepath
+		case "description":
+			entry[field] = page.Description
+		case "tags":
+			entry[field] = page.Tags
+		case "headings":
+			headings := make([]map[string]any, 0)
+			for _, heading := range parser.Headings(page.F) {
+				headings = append(headings, map[string]any{
+					"text": heading.Text,
+					"id":   heading.ID,
+				})
+			}
+			entry[field] = headings
+		case "content":
+			entry[field] = truncateText(searchText(page), s.Config.Search.MaxContentLength)
+		default:
+			if data == nil {
+				data = pageData(page)
+			}
+
+			if value, ok := data[field]; ok {
+				entry[field] = value
+			}
+		}
+	}
+
+	return entry
+}
+
+// the text of a page without the [[toc]] marker
+func searchText(page *parser.Meta) string {
+	text := parser.PlainText(page.F)
+	if !strings.Contains(text, TOC_MARKER_TEXT) {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	lines = slices.DeleteFunc(lines, func(line string) bool {
+		return strings.TrimSpace(line) == TOC_MARKER_TEXT
+	})
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// remove the search index written by a previous build the other way: the
+// shards when the index is a single file, the single file otherwise
+func (s *Server) removeSearchIndex(single bool) error {
+	if !single {
+		err := os.Remove(filepath.Join(s.DestPath, SEARCH_INDEX_FILE))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	shardsPath := filepath.Join(s.DestPath, SEARCH_SHARDS_PATH)
+
+	err := removeSearchShards(shardsPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(shardsPath, "index.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// the folder is only removed when nothing else is in it
+	entries, err := os.ReadDir(shardsPath)
+	if err == nil && len(entries) == 0 {
+		return os.Remove(shardsPath)
+	}
+
+	return nil
+}
+
+// remove the numbered shards of the index, the folder may not exist
+func removeSearchShards(shardsPath string) error {
+	shards, err := filepath.Glob(filepath.Join(shardsPath, "[0-9]*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range shards {
+		err = os.Remove(shard)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pages with `search: false` or `noindex: true` are left out of the index
+func inSearch(page *parser.Meta) bool {
+	if value, ok := page.Frontmatter.Get("search"); ok {
+		if inSearch, ok := frontmatterBool(value); ok && !inSearch {
+			return false
+		}
+	}
+
+	return !isNoindex(page)
+}
+
+// the first length characters of text, 0 keeps all of it
+func truncateText(text string, length int) string {
+	runes := []rune(text)
+	if length == 0 || len(runes) <= length {
+		return text
+	}
+
+	return string(runes[:length])
+}
+
+func writeJSON(path string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return os.WriteFile(path, b, 0644)
+}
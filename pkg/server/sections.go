@@ -0,0 +1,290 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const (
+	SECTION_INDEX_FILE = "_index.md"
+
+	SORT_BY_DATE         = "date"
+	SORT_BY_PUBLISH_DATE = "publishDate"
+	SORT_BY_LASTMOD      = "lastmod"
+	SORT_BY_TITLE        = "title"
+	SORT_BY_WEIGHT       = "weight"
+
+	SORT_ORDER_ASC  = "asc"
+	SORT_ORDER_DESC = "desc"
+)
+
+// folders under content/ are sections, an _index.md in a section renders the
+// list page of the section
+func isSectionIndex(path string) bool {
+	return filepath.Base(path) == SECTION_INDEX_FILE
+}
+
+// render the list page of every section, this runs after the content is
+// rendered so that every page of the section has been read into s.MD
+func (s *Server) processSections() error {
+	log := utils.NewLogger()
+
+	start := time.Now()
+
+	defer func() {
+		log.Debugw("Time taken to process sections", "time", time.Since(start))
+	}()
+
+	sections := s.sectionIndexes()
+
+	for _, section := range sections {
+		rendered, err := s.renderSection(section)
+		if err != nil {
+			return err
+		}
+
+		for sitepath, content := range rendered {
+			renderFolderPath := filepath.Join(s.DestPath, filepath.FromSlash(sitepath))
+
+			err = os.MkdirAll(renderFolderPath, 0755)
+			if err != nil {
+				return err
+			}
+
+			err = section.F.WriteToDest(renderFolderPath, "index.html", []byte(content))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.writeFeeds(&feed{
+			Title:    section.Title,
+			Sitepath: section.Sitepath,
+			Pages:    s.sectionPages(section),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infow("Processed sections", "sections", len(sections))
+
+	return nil
+}
+
+// the published _index.md pages read during the last render
+func (s *Server) sectionIndexes() []*parser.Meta {
+	sections := make([]*parser.Meta, 0)
+
+	s.MD.Range(func(path string, value *parser.Meta) bool {
+		if isSectionIndex(path) && s.isPublished(value) {
+			sections = append(sections, value)
+		}
+		return true
+	})
+
+	return sections
+}
+
+// render the list page of a section, one page per pager, keyed by the
+// sitepath the page is written at
+func (s *Server) renderSection(index *parser.Meta) (map[string]string, error) {
+	pages := s.sectionPages(index)
+	section := sectionData(index, pages)
+
+	pageSize := s.pageSize(index)
+	pagers := paginate(pages, pageSize, index.Sitepath)
+
+	rendered := make(map[string]string, len(pagers))
+	for _, pager := range pagers {
+		content, err := s.renderMarkdown(index, map[string]any{
+			"section":   section,
+			"paginator": pagerData(pagers, pager, pageSize),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rendered[pager.Sitepath] = content
+	}
+
+	return rendered, nil
+}
+
+// every published page of the section, sorted by the `sortBy` and `order` of
+// the _index.md frontmatter. Pages in nested folders are included unless
+// they are in a section of their own, see pageSection.
+func (s *Server) sectionPages(index *parser.Meta) []*parser.Meta {
+	sectionPath := filepath.Dir(index.F.Path)
+
+	pages := make([]*parser.Meta, 0)
+	s.MD.Range(func(path string, value *parser.Meta) bool {
+		if !isSectionIndex(path) &&
+			s.isPublished(value) &&
+			s.pageSection(path) == sectionPath {
+			pages = append(pages, value)
+		}
+		return true
+	})
+
+	sortBy, _ := index.Frontmatter.Get("sortBy")
+	order, _ := index.Frontmatter.Get("order")
+
+	sortPages(
+		pages,
+		utils.GetSafeValue[string](sortBy),
+		utils.GetSafeValue[string](order),
+	)
+
+	return pages
+}
+
+// the folder of the closest published _index.md above a page, "" when the
+// page is in no section
+func (s *Server) pageSection(path string) string {
+	contentPath := filepath.Join(s.SrcPath, "content")
+
+	for dir := filepath.Dir(path); strings.HasPrefix(dir, contentPath); dir = filepath.Dir(dir) {
+		if index, ok := s.MD.Get(filepath.Join(dir, SECTION_INDEX_FILE)); ok && s.isPublished(index) {
+			return dir
+		}
+
+		if dir == contentPath {
+			break
+		}
+	}
+
+	return ""
+}
+
+// values of a section exposed to its list page as {{ $section.* }}, pages
+// holds every page of the section, see $paginator for the pages of the
+// current page
+func sectionData(index *parser.Meta, pages []*parser.Meta) map[string]any {
+	pagesData := make([]map[string]any, len(pages))
+	for i, page := range pages {
+		pagesData[i] = pageData(page)
+	}
+
+	return map[string]any{
+		"title":    index.Title,
+		"sitepath": index.Sitepath,
+		"pages":    pagesData,
+	}
+}
+
+// sort pages by date (newest first), title or weight (both ascending),
+// order flips the default direction. Pages missing the value go last.
+func sortPages(pages []*parser.Meta, sortBy string, order string) {
+	if sortBy == "" {
+		sortBy = SORT_BY_DATE
+	}
+
+	// newest first by default
+	desc := sortBy == SORT_BY_DATE || sortBy == SORT_BY_PUBLISH_DATE || sortBy == SORT_BY_LASTMOD
+	switch order {
+	case SORT_ORDER_ASC:
+		desc = false
+	case SORT_ORDER_DESC:
+		desc = true
+	}
+
+	// compare returns < 0 when a comes first in ascending order, ok is false
+	// when either page is missing the value
+	compare := func(a, b *parser.Meta) (int, bool) {
+		switch sortBy {
+		case SORT_BY_TITLE:
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)), true
+		case SORT_BY_WEIGHT:
+			aWeight, aOk := frontmatterNumber(a, SORT_BY_WEIGHT)
+			bWeight, bOk := frontmatterNumber(b, SORT_BY_WEIGHT)
+			if !aOk || !bOk {
+				return boolOrder(aOk, bOk), false
+			}
+			return floatOrder(aWeight, bWeight), true
+		case SORT_BY_PUBLISH_DATE:
+			return a.PublishDate.Compare(b.PublishDate), true
+		case SORT_BY_LASTMOD:
+			return a.LastModified.Compare(b.LastModified), true
+		default:
+			return a.Date.Compare(b.Date), true
+		}
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		c, ok := compare(pages[i], pages[j])
+		if ok && desc {
+			c = -c
+		}
+
+		if c == 0 {
+			// keep the order stable across builds
+			return pages[i].F.Path < pages[j].F.Path
+		}
+
+		return c < 0
+	})
+}
+
+// pages having a value come before pages missing it
+func boolOrder(a, b bool) int {
+	switch {
+	case a && !b:
+		return -1
+	case !a && b:
+		return 1
+	}
+
+	return 0
+}
+
+func floatOrder(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func frontmatterTime(page *parser.Meta, key string) (time.Time, bool) {
+	value, ok := page.Frontmatter.Get(key)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return utils.ParseTime(value)
+}
+
+func frontmatterNumber(page *parser.Meta, key string) (float64, bool) {
+	value, ok := page.Frontmatter.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		var f float64
+		_, err := fmt.Sscan(v, &f)
+		return f, err == nil
+	}
+
+	return 0, false
+}
@@ -1,14 +1,19 @@
 package server
 
 import (
+	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/aarol/reload"
 	"github.com/fsnotify/fsnotify"
 	"github.com/shreyaskaundinya/garlic/models"
+	gconfig "github.com/shreyaskaundinya/garlic/pkg/config"
 	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/templating"
 	"github.com/shreyaskaundinya/garlic/pkg/utils"
 )
 
@@ -33,106 +38,55 @@ func checkAndCreateFolder(path, aliasName string) error {
 	return nil
 }
 
-func checkAndSeedFile(srcPath, contentPath, aliasName string) error {
+func checkAndSeedFile(srcPath string, fsys fs.FS, contentPath, aliasName string) (bool, error) {
 	log := utils.NewLogger()
 
 	srcFileExist, srcFileErr := utils.PathExists(srcPath)
 
 	if srcFileErr != nil {
-		return srcFileErr
+		return false, srcFileErr
 	}
 
-	if !srcFileExist {
-		log.Infow(aliasName + " does not exist, creating it...")
-
-		// read the content file
-		content, err := os.ReadFile(contentPath)
-		if err != nil {
-			return err
-		}
-
-		// create the index MD file
-		err = os.WriteFile(srcPath, content, 0644)
-		if err != nil {
-			return err
-		}
+	if srcFileExist {
+		return false, nil
 	}
 
-	return nil
-}
-
-func seedSrc(config *models.Config) error {
-	SrcPath := config.SrcPath
-	return checkAndCreateFolder(SrcPath, "src")
-}
-
-func seedContent(config *models.Config) error {
-	SrcPath := config.SrcPath
-	blogsPath := filepath.Join(SrcPath, "content")
+	log.Infow(aliasName + " does not exist, creating it...")
 
-	err := checkAndCreateFolder(blogsPath, "src/content")
+	// read the content file
+	content, err := fs.ReadFile(fsys, contentPath)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	// create the default content files
-	if config.ShouldSeedFiles {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-
-		indexMDPath := filepath.Join(blogsPath, "index.md")
-
-		defaultContentPath := filepath.Join(cwd, "default-assets", "content", "index.md")
-
-		err = checkAndSeedFile(indexMDPath, defaultContentPath, "Index MD")
-		if err != nil {
-			return err
-		}
+	// create the file
+	err = os.WriteFile(srcPath, content, 0644)
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	return true, nil
 }
 
-func seedTemplates(config *models.Config) error {
-	SrcPath := config.SrcPath
+// folders every source folder needs, created even when files are not seeded
+var srcFolders = []string{
+	"content",
+	"templates",
+	"components",
+	"assets",
+	"data",
+}
 
-	templatesPath := filepath.Join(SrcPath, "templates")
+func seedSrc(config *models.Config) error {
+	SrcPath := config.SrcPath
 
-	err := checkAndCreateFolder(templatesPath, "src/templates")
+	err := checkAndCreateFolder(SrcPath, "src")
 	if err != nil {
 		return err
 	}
 
-	// seed the default templates
-	if config.ShouldSeedFiles {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-
-		// templates/index.html
-		indexHTMLPath := filepath.Join(templatesPath, "index.html")
-		defaultTemplatePath := filepath.Join(cwd, "default-assets", "templates", "index.html")
-		err = checkAndSeedFile(indexHTMLPath, defaultTemplatePath, "Index HTML")
-		if err != nil {
-			return err
-		}
-
-		// templates/_tags.html
-		tagsHTMLPath := filepath.Join(templatesPath, "_tags.html")
-		defaultTemplatePath = filepath.Join(cwd, "default-assets", "templates", "_tags.html")
-		err = checkAndSeedFile(tagsHTMLPath, defaultTemplatePath, "Tags HTML")
-		if err != nil {
-			return err
-		}
-
-		// templates/_individual_tag.html
-		individualTagHTMLPath := filepath.Join(templatesPath, "_individual_tag.html")
-		defaultTemplatePath = filepath.Join(cwd, "default-assets", "templates", "_individual_tag.html")
-
-		err = checkAndSeedFile(individualTagHTMLPath, defaultTemplatePath, "Individual Tag HTML")
+	for _, folder := range srcFolders {
+		err = checkAndCreateFolder(filepath.Join(SrcPath, folder), path.Join("src", folder))
 		if err != nil {
 			return err
 		}
@@ -141,138 +95,84 @@ func seedTemplates(config *models.Config) error {
 	return nil
 }
 
-func seedComponents(config *models.Config) error {
-	SrcPath := config.SrcPath
-
-	componentsPath := filepath.Join(SrcPath, "components")
-
-	err := checkAndCreateFolder(componentsPath, "src/components")
-	if err != nil {
-		return err
-	}
-
-	// seed the default components
-	if config.ShouldSeedFiles {
-		cwd, err := os.Getwd()
+// copy every file of the theme into the source folder, files that already
+// exist in the source folder are left untouched
+func seedFiles(config *models.Config, theme fs.FS, report *SeedReport) error {
+	return fs.WalkDir(theme, ".", func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// components/Footerbar.html
-		footerbarHTMLPath := filepath.Join(componentsPath, "Footerbar.html")
-		defaultComponentPath := filepath.Join(cwd, "default-assets", "components", "Footerbar.html")
-		err = checkAndSeedFile(footerbarHTMLPath, defaultComponentPath, "Footerbar HTML")
-		if err != nil {
-			return err
+		if p == "." {
+			return nil
 		}
 
-		// components/Navbar.html
-		navbarHTMLPath := filepath.Join(componentsPath, "Navbar.html")
-		defaultComponentPath = filepath.Join(cwd, "default-assets", "components", "Navbar.html")
-		err = checkAndSeedFile(navbarHTMLPath, defaultComponentPath, "Navbar HTML")
-		if err != nil {
-			return err
-		}
+		srcPath := filepath.Join(config.SrcPath, filepath.FromSlash(p))
+		aliasName := path.Join("src", p)
 
-		// components/Tags.html
-		tagsHTMLPath := filepath.Join(componentsPath, "Tags.html")
-		defaultComponentPath = filepath.Join(cwd, "default-assets", "components", "Tags.html")
-		err = checkAndSeedFile(tagsHTMLPath, defaultComponentPath, "Tags HTML")
-		if err != nil {
-			return err
+		if d.IsDir() {
+			return checkAndCreateFolder(srcPath, aliasName)
 		}
-	}
-
-	return nil
-}
 
-func seedAssets(config *models.Config) error {
-	SrcPath := config.SrcPath
-
-	assetsPath := filepath.Join(SrcPath, "assets")
-
-	err := checkAndCreateFolder(assetsPath, "src/assets")
-	if err != nil {
-		return err
-	}
-
-	// seed the default assets
-	if config.ShouldSeedFiles {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-
-		// styles folder
-		stylesFolderPath := filepath.Join(assetsPath, "styles")
-
-		err = checkAndCreateFolder(stylesFolderPath, "src/assets/styles")
+		created, err := checkAndSeedFile(srcPath, theme, p, aliasName)
 		if err != nil {
 			return err
 		}
 
-		// assets/styles/global.css
-		globalCSSPath := filepath.Join(assetsPath, "styles", "global.css")
-		defaultAssetPath := filepath.Join(cwd, "default-assets", "assets", "styles", "global.css")
-		err = checkAndSeedFile(globalCSSPath, defaultAssetPath, "Global CSS")
-		if err != nil {
-			return err
+		if created {
+			report.Created = append(report.Created, p)
+		} else {
+			report.Skipped = append(report.Skipped, p)
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
 func seedDest(config *models.Config) error {
 	return checkAndCreateFolder(config.DestPath, "Destination")
 }
 
-func seed(config *models.Config) error {
-	err := seedSrc(config)
-	if err != nil {
-		return err
-	}
-
-	err = seedContent(config)
-	if err != nil {
-		return err
-	}
-
-	err = seedTemplates(config)
-	if err != nil {
-		return err
+// Seed creates the source and destination folders if missing, and when
+// config.ShouldSeedFiles is set copies the files of the theme into the
+// source folder
+func Seed(config *models.Config, theme fs.FS) (*SeedReport, error) {
+	report := &SeedReport{
+		Created: make([]string, 0),
+		Skipped: make([]string, 0),
 	}
 
-	err = seedComponents(config)
+	err := seedSrc(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = seedAssets(config)
-	if err != nil {
-		return err
+	if config.ShouldSeedFiles && theme != nil {
+		err = seedFiles(config, theme, report)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	err = seedDest(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return report, nil
 }
 
 func NewServer(config *models.Config) (*Server, error) {
-	log := utils.NewLogger()
-
-	err := seed(config)
-	if err != nil {
-		log.Errorw("Error seeding", "error", err)
-		return nil, err
-	}
+	slugOptions := gconfig.SlugOptions(config)
 
 	return &Server{
-		SrcPath:      filepath.FromSlash(config.SrcPath),
-		DestPath:     filepath.FromSlash(config.DestPath),
+		SrcPath:      utils.CleanPath(config.SrcPath),
+		DestPath:     utils.CleanPath(config.DestPath),
+		ThemePath:    utils.CleanPath(config.Theme),
+		Config:       config,
+		Site:         gconfig.SiteData(config),
+		SlugOptions:  slugOptions,
+		Filters:      templating.Filters(slugOptions),
 		MD:           parser.NewMetadataMap(),
 		TemplateMD:   parser.NewMetadataMap(),
 		ComponentsMD: parser.NewMetadataMap(),
@@ -283,13 +183,20 @@ func NewServer(config *models.Config) (*Server, error) {
 	}, nil
 }
 
-func (s *Server) Start(config *models.Config) {
+// Build renders the whole site once
+func (s *Server) Build() error {
+	return s.render(&RenderEvent{RenderAll: true})
+}
+
+// Start renders the whole site, then watches the source folder for changes
+// and serves the destination folder until the process is stopped
+func (s *Server) Start() error {
 	log := utils.NewLogger()
 
 	// need to add a watcher to check for changes in the source folder
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		log.Fatalw("Error creating watcher", "error", err)
+		return fmt.Errorf("error creating watcher: %w", err)
 	}
 	defer watcher.Close()
 
@@ -304,7 +211,7 @@ func (s *Server) Start(config *models.Config) {
 
 				log.Infow("modified file:", "event", event.Name)
 
-				err = s.render(&RenderEvent{Event: event, RenderAll: false})
+				err := s.render(&RenderEvent{Event: event, RenderAll: false})
 				if err != nil {
 					log.Errorw("Error rendering", "error", err)
 				}
@@ -317,43 +224,43 @@ func (s *Server) Start(config *models.Config) {
 		}
 	}()
 
-	// walk all the paths inside source and add all the directories to the watcher
-	err = filepath.WalkDir(s.SrcPath, func(path string, info os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
+	// walk all the paths inside source (and the theme) and add all the
+	// directories to the watcher
+	watchPaths := []string{s.SrcPath}
+	if s.ThemePath != "" {
+		watchPaths = append(watchPaths, s.ThemePath)
+	}
 
-		if info.IsDir() {
-			err = watcher.Add(path)
+	for _, watchPath := range watchPaths {
+		err = filepath.WalkDir(watchPath, func(path string, info os.DirEntry, err error) error {
 			if err != nil {
-				log.Errorw("Error adding watcher", "error", err)
 				return err
 			}
+
+			if info.IsDir() {
+				err = watcher.Add(path)
+				if err != nil {
+					log.Errorw("Error adding watcher", "error", err)
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error walking %s: %w", watchPath, err)
 		}
-		return nil
-	})
-	if err != nil {
-		log.Errorw("Error walking source", "error", err)
-		return
 	}
 
 	// read templates and render once on init
-	err = s.render(&RenderEvent{RenderAll: true})
+	err = s.Build()
 	if err != nil {
-		log.Errorw("Error rendering", "error", err)
-		return
+		return err
 	}
 
-	// serve the html files
-	if config.ShouldServe {
-		go s.serve()
-
-		// Block main goroutine forever.
-		<-make(chan struct{})
-	}
+	return s.serve()
 }
 
-func (s *Server) serve() {
+func (s *Server) serve() error {
 	log := utils.NewLogger()
 
 	fs := http.FileServer(http.Dir(s.DestPath))
@@ -372,5 +279,9 @@ func (s *Server) serve() {
 	// Use the Handle() method as a middleware
 	handler = reloader.Handle(handler)
 
-	http.ListenAndServe(":8084", handler)
+	addr := fmt.Sprintf(":%d", s.Config.Port)
+
+	log.Infow("Serving", "address", fmt.Sprintf("http://localhost%s", addr))
+
+	return http.ListenAndServe(addr, handler)
 }
@@ -1,8 +1,13 @@
 package server
 
 import (
+	"html/template"
+	"time"
+
 	"github.com/fsnotify/fsnotify"
+	"github.com/shreyaskaundinya/garlic/models"
 	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
 )
 
 /*
@@ -21,6 +26,24 @@ type Server struct {
 	// destination folder path
 	DestPath string
 
+	// theme folder path, empty when the site does not use a theme
+	ThemePath string
+
+	// site config
+	Config *models.Config
+
+	// config values exposed to templates as {{ $site.* }}
+	Site map[string]any
+
+	// files of the data folder exposed to templates as {{ $data.* }}
+	Data map[string]any
+
+	// how taxonomy terms are turned into url paths
+	SlugOptions utils.SlugOptions
+
+	// functions available to templates and components
+	Filters template.FuncMap
+
 	// metadata
 	MD *parser.Metadata
 
@@ -33,6 +56,9 @@ type Server struct {
 	// Parser
 	Parser *parser.Parser
 
+	// date of the last commit of each file, keyed by absolute path (gitInfo)
+	gitDates map[string]time.Time
+
 	// file chan
 	fileCh chan *parser.File
 
@@ -48,6 +74,27 @@ type RenderEvent struct {
 	RenderAll           bool
 	ProcessAssets       bool
 	ProcessDependencies bool
+	ProcessData         bool
 	ProcessContent      bool
-	ProcessTags         bool
+	ProcessTaxonomies   bool
+	ProcessSections     bool
+}
+
+// one page of a paginated list (section list pages, tag pages)
+type Pager struct {
+	// 1 based
+	Number int
+
+	// where the page is written, /blog/ for the first page and
+	// /blog/page/2/ for the rest
+	Sitepath string
+
+	// pages listed on this page
+	Pages []*parser.Meta
+}
+
+// files written or left untouched while seeding the source folder
+type SeedReport struct {
+	Created []string
+	Skipped []string
 }
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	gconfig "github.com/shreyaskaundinya/garlic/pkg/config"
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+const (
+	SITEMAP_FILE = "sitemap.xml"
+	ROBOTS_FILE  = "robots.txt"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+// the sitemap is written unless disabled, its urls have to be absolute so the
+// site needs a baseURL
+func (s *Server) sitemapEnabled() bool {
+	if s.Config.Sitemap.Enabled != nil && !*s.Config.Sitemap.Enabled {
+		return false
+	}
+
+	return s.Config.BaseURL != ""
+}
+
+// write sitemap.xml listing every published page, section list page and
+// taxonomy page, and robots.txt pointing to it
+func (s *Server) processSitemap() error {
+	log := utils.NewLogger()
+
+	if s.sitemapEnabled() {
+		urls := s.sitemapURLs()
+
+		sitemap, err := marshalXML(&sitemapURLSet{URLs: urls})
+		if err != nil {
+			return fmt.Errorf("error writing the sitemap: %w", err)
+		}
+
+		err = os.WriteFile(filepath.Join(s.DestPath, SITEMAP_FILE), sitemap, 0644)
+		if err != nil {
+			return err
+		}
+
+		log.Infow("Processed sitemap", "urls", len(urls))
+	} else {
+		log.Debugw("Sitemap is disabled or the site has no baseURL, skipping it")
+	}
+
+	if s.Config.Robots.Enabled != nil && !*s.Config.Robots.Enabled {
+		return nil
+	}
+
+	return os.WriteFile(filepath.Join(s.DestPath, ROBOTS_FILE), []byte(s.robots()), 0644)
+}
+
+func (s *Server) sitemapURLs() []sitemapURL {
+	urls := make([]sitemapURL, 0)
+
+	// pages and the list pages of sections, the last change of a list page
+	// is the last change of its pages. Lists split into pages (/blog/page/2/)
+	// list every page after the first with the settings of the first.
+	s.MD.Range(func(path string, page *parser.Meta) bool {
+		if !s.isPublished(page) || !inSitemap(page) {
+			return true
+		}
+
+		if !isSectionIndex(path) {
+			urls = append(urls, s.sitemapURL(page.Sitepath, page.LastModified, page))
+			return true
+		}
+
+		sectionPages := s.sectionPages(page)
+		urls = append(urls, s.sitemapURL(page.Sitepath, latestChange(append(sectionPages, page)), page))
+
+		pagers := paginate(sectionPages, s.pageSize(page), page.Sitepath)
+		for _, pager := range pagers[1:] {
+			urls = append(urls, s.sitemapURL(pager.Sitepath, latestChange(pager.Pages), page))
+		}
+
+		return true
+	})
+
+	for _, name := range slices.Sorted(maps.Keys(s.Config.Taxonomies)) {
+		taxonomy := s.Config.Taxonomies[name]
+		taxonomyPath := "/" + taxonomy.Path
+
+		terms := s.taxonomyTerms(name, taxonomyPath)
+
+		// term pages are split like in processTaxonomy
+		termTemplate, _ := s.getTemplate(taxonomy.TermTemplate)
+		pageSize := s.pageSize(termTemplate)
+
+		pages := make([]*parser.Meta, 0)
+		for _, term := range terms {
+			pages = append(pages, term.Pages...)
+
+			sortPages(term.Pages, SORT_BY_DATE, "")
+			for _, pager := range paginate(term.Pages, pageSize, term.Sitepath) {
+				lastModified := latestChange(pager.Pages)
+				if pager.Number == 1 {
+					lastModified = latestChange(term.Pages)
+				}
+
+				urls = append(urls, s.sitemapURL(pager.Sitepath, lastModified, nil))
+			}
+		}
+
+		urls = append(urls, s.sitemapURL(taxonomyPath, latestChange(pages), nil))
+	}
+
+	slices.SortFunc(urls, func(a, b sitemapURL) int {
+		return strings.Compare(a.Loc, b.Loc)
+	})
+
+	return urls
+}
+
+// the sitemap entry of a sitepath, page is nil for taxonomy pages which use
+// the defaults of the config
+func (s *Server) sitemapURL(sitepath string, lastModified time.Time, page *parser.Meta) sitemapURL {
+	url := sitemapURL{
+		Loc:        s.absoluteURL(sitepath),
+		ChangeFreq: s.Config.Sitemap.ChangeFreq,
+	}
+
+	if !lastModified.IsZero() {
+		url.LastMod = lastModified.Format(time.RFC3339)
+	}
+
+	// 0 in the config leaves the priority out, a page can still set 0
+	priority := s.Config.Sitemap.Priority
+	hasPriority := priority > 0
+
+	if page != nil {
+		changeFreq, _ := page.Frontmatter.Get("changefreq")
+		if changeFreq := utils.GetSafeValue[string](changeFreq); gconfig.IsChangeFrequency(changeFreq) {
+			url.ChangeFreq = changeFreq
+		}
+
+		if pagePriority, ok := frontmatterNumber(page, "priority"); ok && pagePriority >= 0 && pagePriority <= 1 {
+			priority = pagePriority
+			hasPriority = true
+		}
+	}
+
+	if hasPriority {
+		url.Priority = strconv.FormatFloat(priority, 'f', -1, 64)
+	}
+
+	return url
+}
+
+// pages with `sitemap: false` or `noindex: true` are left out of the sitemap
+func inSitemap(page *parser.Meta) bool {
+	if value, ok := page.Frontmatter.Get("sitemap"); ok {
+		if inSitemap, ok := frontmatterBool(value); ok && !inSitemap {
+			return false
+		}
+	}
+
+	return !isNoindex(page)
+}
+
+// pages with `noindex: true` are hidden from search engines and the search
+// index
+func isNoindex(page *parser.Meta) bool {
+	value, ok := page.Frontmatter.Get("noindex")
+	if !ok {
+		return false
+	}
+
+	noindex, ok := frontmatterBool(value)
+
+	return ok && noindex
+}
+
+// the last change of any of the pages
+func latestChange(pages []*parser.Meta) time.Time {
+	var latest time.Time
+
+	for _, page := range pages {
+		if page.LastModified.After(latest) {
+			latest = page.LastModified
+		}
+	}
+
+	return latest
+}
+
+func (s *Server) robots() string {
+	var b strings.Builder
+
+	b.WriteString("User-agent: *\n")
+
+	if len(s.Config.Robots.Disallow) == 0 {
+		b.WriteString("Allow: /\n")
+	}
+
+	for _, disallow := range s.Config.Robots.Disallow {
+		fmt.Fprintf(&b, "Disallow: %s\n", disallow)
+	}
+
+	if s.sitemapEnabled() {
+		fmt.Fprintf(&b, "\nSitemap: %s\n", s.absoluteURL("/"+SITEMAP_FILE))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+func TestSitemapURLsPagers(t *testing.T) {
+	tests := []struct {
+		name     string
+		posts    int
+		pageSize int
+		want     []string
+	}{
+		{
+			name:     "one page per list",
+			posts:    2,
+			pageSize: 0,
+			want:     []string{"/posts/", "/posts/p1", "/posts/p2", "/tags", "/tags/go"},
+		},
+		{
+			name:     "lists split into pages",
+			posts:    5,
+			pageSize: 2,
+			want: []string{
+				"/posts/", "/posts/p1", "/posts/p2", "/posts/p3", "/posts/p4", "/posts/p5",
+				"/posts/page/2/", "/posts/page/3/",
+				"/tags", "/tags/go", "/tags/go/page/2/", "/tags/go/page/3/",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewServer(&models.Config{
+				SrcPath:          "site",
+				PublishByDefault: true,
+				Pagination:       models.PaginationConfig{PageSize: tt.pageSize},
+				Taxonomies: map[string]models.TaxonomyConfig{
+					"tags": {Path: "tags", TermTemplate: "_individual_tag"},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			section := filepath.Join("site", "content", "posts")
+
+			index := testPages(1)[0]
+			index.Sitepath = "/posts/"
+			index.F = &parser.File{Path: filepath.Join(section, SECTION_INDEX_FILE)}
+			s.MD.Set(index.F.Path, index)
+
+			for i, page := range testPages(tt.posts) {
+				page.Sitepath = fmt.Sprintf("/posts/p%d", i+1)
+				page.F = &parser.File{Path: filepath.Join(section, fmt.Sprintf("p%d.md", i+1))}
+				page.Frontmatter.Set("tags", []any{"go"})
+				s.MD.Set(page.F.Path, page)
+			}
+
+			got := make([]string, 0)
+			for _, url := range s.sitemapURLs() {
+				got = append(got, url.Loc)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/shreyaskaundinya/garlic/models"
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/templating"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+	"golang.org/x/net/html"
+)
+
+// a term of a taxonomy (ie: the tag "Go") and the pages using it, the name
+// is shown as written in the frontmatter while the page lives at its slug
+type taxonomyTerm struct {
+	Name     string
+	Slug     string
+	Sitepath string
+	Pages    []*parser.Meta
+}
+
+// render the pages of every taxonomy in the config
+func (s *Server) processTaxonomies() error {
+	for _, name := range slices.Sorted(maps.Keys(s.Config.Taxonomies)) {
+		err := s.processTaxonomy(name, s.Config.Taxonomies[name])
+		if err != nil {
+			return fmt.Errorf("taxonomy %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// a taxonomy gets a page listing all of its terms at /<path> and a page per
+// term listing the pages using it at /<path>/<term>
+func (s *Server) processTaxonomy(name string, taxonomy models.TaxonomyConfig) error {
+	log := utils.NewLogger()
+
+	log.Debugw("Processing taxonomy", "taxonomy", name)
+
+	start := time.Now()
+
+	defer func() {
+		log.Debugw("Time taken to process taxonomy", "taxonomy", name, "time", time.Since(start))
+	}()
+
+	taxonomyPath := "/" + taxonomy.Path
+
+	// need to generate a page with all terms
+	terms := s.taxonomyTerms(name, taxonomyPath)
+
+	// find the template for the list of terms
+	listTemplate, layers, err := s.taxonomyLayers(name, taxonomy.ListTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("Taxonomy list template", "taxonomy", name, "template", taxonomy.ListTemplate, "found", listTemplate != nil)
+
+	termsList := &html.Node{
+		Type: html.ElementNode,
+		Data: "ul",
+	}
+
+	for _, term := range terms {
+		li := &html.Node{
+			Type: html.ElementNode,
+			Data: "li",
+		}
+
+		a := &html.Node{
+			Type: html.ElementNode,
+			Data: "a",
+		}
+
+		a.Attr = append(a.Attr, html.Attribute{
+			Key: "href",
+			Val: term.Sitepath,
+		})
+
+		a.AppendChild(&html.Node{
+			Type: html.TextNode,
+			Data: fmt.Sprintf("%s ( %d )", term.Name, len(term.Pages)),
+		})
+
+		li.AppendChild(a)
+
+		termsList.AppendChild(li)
+	}
+
+	termsListHTML, err := renderNode(termsList)
+	if err != nil {
+		return err
+	}
+
+	data := s.templateData(&parser.Meta{
+		Title:    taxonomyTitle(name),
+		Sitepath: taxonomyPath,
+	}, termsListHTML)
+	data["taxonomy"] = taxonomyData(name, taxonomyPath, terms)
+
+	termsHTML, err := s.renderTemplate(layers, data)
+	if err != nil {
+		return err
+	}
+
+	err = s.writeTaxonomyPage(taxonomyPath, termsHTML)
+	if err != nil {
+		return err
+	}
+
+	termTemplate, layers, err := s.taxonomyLayers(name, taxonomy.TermTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("Taxonomy term template", "taxonomy", name, "template", taxonomy.TermTemplate, "found", termTemplate != nil)
+
+	// create a page for each term, split into pages of pageSize pages (from
+	// the frontmatter of the term template or the config)
+	pageSize := s.pageSize(termTemplate)
+
+	for _, term := range terms {
+		sortPages(term.Pages, SORT_BY_DATE, "")
+
+		pagers := paginate(term.Pages, pageSize, term.Sitepath)
+
+		for _, pager := range pagers {
+			termList := &html.Node{
+				Type: html.ElementNode,
+				Data: "ul",
+			}
+
+			for _, meta := range pager.Pages {
+				if meta == nil {
+					continue
+				}
+
+				li := &html.Node{
+					Type: html.ElementNode,
+					Data: "li",
+				}
+
+				a := &html.Node{
+					Type: html.ElementNode,
+					Data: "a",
+				}
+
+				a.Attr = append(a.Attr, html.Attribute{
+					Key: "href",
+					Val: meta.Sitepath,
+				})
+
+				a.AppendChild(&html.Node{
+					Type: html.TextNode,
+					Data: meta.Title,
+				})
+
+				li.AppendChild(a)
+
+				termList.AppendChild(li)
+			}
+
+			termListHTML, err := renderNode(termList)
+			if err != nil {
+				return err
+			}
+
+			data := s.templateData(&parser.Meta{
+				Title:    term.Name,
+				Sitepath: pager.Sitepath,
+			}, termListHTML)
+
+			termTaxonomy := taxonomyData(name, taxonomyPath, terms)
+			termTaxonomy["term"] = termData(term)
+			data["taxonomy"] = termTaxonomy
+			data["paginator"] = pagerData(pagers, pager, pageSize)
+
+			termHTML, err := s.renderTemplate(layers, data)
+			if err != nil {
+				return err
+			}
+
+			err = s.writeTaxonomyPage(pager.Sitepath, termHTML)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.writeFeeds(&feed{
+			Title:    term.Name,
+			Sitepath: term.Sitepath,
+			Pages:    term.Pages,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// the terms used by the published pages, sorted by name. The terms are
+// slugified into their paths, terms with the same slug are merged into the
+// first of them and terms without a slug are skipped.
+func (s *Server) taxonomyTerms(name string, taxonomyPath string) []*taxonomyTerm {
+	log := utils.NewLogger()
+
+	termToFilesMap := map[string][]*parser.Meta{}
+	termsMapset := mapset.NewSet[string]()
+
+	s.MD.Store.Range(func(_ string, value *parser.Meta) bool {
+		if !s.isPublished(value) {
+			return true
+		}
+
+		for _, term := range value.Frontmatter.GetTerms(name) {
+			termToFilesMap[term] = append(termToFilesMap[term], value)
+			termsMapset.Add(term)
+		}
+		return true
+	})
+
+	termNames := termsMapset.ToSlice()
+
+	log.Debugw("Terms", "taxonomy", name, "terms", termNames)
+
+	sort.Strings(termNames)
+
+	terms := make([]*taxonomyTerm, 0, len(termNames))
+	slugs := map[string]*taxonomyTerm{}
+
+	for _, term := range termNames {
+		slug := utils.Slugify(term, s.SlugOptions)
+
+		// a term like 🚀 has nothing to build a path from, the pages using
+		// it are still rendered
+		if slug == "" {
+			log.Warnw(
+				"Term has no letters or digits to build its path from, skipping it",
+				"taxonomy", name,
+				"term", term,
+			)
+			continue
+		}
+
+		// terms with the same slug (Go and go) share one page, named after
+		// the first of them
+		if first, ok := slugs[slug]; ok {
+			log.Warnw(
+				"Terms use the same path, merging them",
+				"taxonomy", name,
+				"term", term,
+				"into", first.Name,
+				"path", first.Sitepath,
+			)
+
+			for _, page := range termToFilesMap[term] {
+				if !slices.Contains(first.Pages, page) {
+					first.Pages = append(first.Pages, page)
+				}
+			}
+
+			continue
+		}
+
+		slugs[slug] = &taxonomyTerm{
+			Name:     term,
+			Slug:     slug,
+			Sitepath: fmt.Sprintf("%s/%s", taxonomyPath, slug),
+			Pages:    termToFilesMap[term],
+		}
+
+		terms = append(terms, slugs[slug])
+	}
+
+	return terms
+}
+
+// the template (and the layouts it extends) of a taxonomy page, a template
+// that only outputs the list is used when the site does not have one
+func (s *Server) taxonomyLayers(taxonomy string, name string) (*parser.Meta, []templating.Layer, error) {
+	t, ok := s.getTemplate(name)
+	if !ok {
+		return nil, []templating.Layer{{
+			Name: taxonomy,
+			Body: []byte(`
+			{{ $content }}
+		`),
+		}}, nil
+	}
+
+	layers, err := s.templateLayers(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, layers, nil
+}
+
+func (s *Server) writeTaxonomyPage(sitepath string, content string) error {
+	destPath := filepath.Join(s.DestPath, filepath.FromSlash(sitepath))
+
+	err := os.MkdirAll(destPath, 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(destPath, "index.html"), []byte(content), 0644)
+}
+
+// title of the page listing the terms of a taxonomy, ie: tags -> Tags
+func taxonomyTitle(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// values of a taxonomy exposed to its templates as {{ $taxonomy.* }}, term
+// pages also get the term they list as {{ $taxonomy.term }}
+func taxonomyData(name string, sitepath string, terms []*taxonomyTerm) map[string]any {
+	termsData := make([]map[string]any, len(terms))
+	for i, term := range terms {
+		termsData[i] = termData(term)
+	}
+
+	return map[string]any{
+		"name":     name,
+		"title":    taxonomyTitle(name),
+		"sitepath": sitepath,
+		"terms":    termsData,
+	}
+}
+
+func termData(term *taxonomyTerm) map[string]any {
+	return map[string]any{
+		"name":     term.Name,
+		"slug":     term.Slug,
+		"sitepath": term.Sitepath,
+		"count":    len(term.Pages),
+	}
+}
+
+// render a generated node (ie: the list of tags) into trusted html for templates
+func renderNode(n *html.Node) (htmltemplate.HTML, error) {
+	b := bytes.NewBuffer(make([]byte, 0))
+
+	err := html.Render(b, n)
+	if err != nil {
+		return "", err
+	}
+
+	return htmltemplate.HTML(b.String()), nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shreyaskaundinya/garlic/models"
+)
+
+func TestTaxonomyTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages [][]any
+		want  string
+	}{
+		{
+			name:  "no terms",
+			pages: [][]any{{}, {}},
+			want:  "",
+		},
+		{
+			name:  "sorted by name",
+			pages: [][]any{{"writing", "go"}, {"go"}},
+			want:  "go:/tags/go:2 writing:/tags/writing:1",
+		},
+		{
+			name:  "terms are slugified",
+			pages: [][]any{{"C++ & Go"}},
+			want:  "C++ & Go:/tags/c-go:1",
+		},
+		{
+			name:  "terms with the same slug are merged into the first",
+			pages: [][]any{{"Go"}, {"go"}, {"Go", "go"}},
+			want:  "Go:/tags/go:3",
+		},
+		{
+			name:  "terms without a slug are skipped",
+			pages: [][]any{{"🚀", "go"}, {"?!"}},
+			want:  "go:/tags/go:1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewServer(&models.Config{PublishByDefault: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, page := range testPages(len(tt.pages)) {
+				page.Frontmatter.Set("tags", tt.pages[i])
+				s.MD.Set(fmt.Sprintf("page-%d.md", i), page)
+			}
+
+			got := make([]string, 0)
+			for _, term := range s.taxonomyTerms("tags", "/tags") {
+				got = append(got, fmt.Sprintf("%s:%s:%d", term.Name, term.Sitepath, len(term.Pages)))
+			}
+
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("got %q, want %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
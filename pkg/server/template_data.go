@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+// values of a page exposed to templates as {{ $page.* }}, every frontmatter
+// field is available along with the computed ones. Dates are times, format
+// them with the date filter.
+func pageData(fileMetadata *parser.Meta) map[string]any {
+	page := map[string]any{}
+
+	if fileMetadata.Frontmatter != nil {
+		for key, value := range fileMetadata.Frontmatter.Store {
+			page[key] = utils.NormalizeValue(value)
+		}
+	}
+
+	page["title"] = fileMetadata.Title
+	page["description"] = fileMetadata.Description
+	page["sitepath"] = fileMetadata.Sitepath
+	page["tags"] = fileMetadata.Tags
+	page["date"] = fileMetadata.Date
+	page["publishDate"] = fileMetadata.PublishDate
+	page["lastmod"] = fileMetadata.LastModified
+
+	return page
+}
+
+// tags of a page as <li><a href="/tags/<slug>">tag</a></li>, kept for
+// templates using the {{ $tags }} placeholder
+func (s *Server) tagsHTML(tags []string) template.HTML {
+	tagsPath := "tags"
+	if taxonomy, ok := s.Config.Taxonomies["tags"]; ok {
+		tagsPath = taxonomy.Path
+	}
+
+	var b strings.Builder
+
+	for _, tag := range tags {
+		fmt.Fprintf(
+			&b,
+			`<li><a href="/%s/%s">%s</a></li>`,
+			html.EscapeString(tagsPath),
+			html.EscapeString(utils.Slugify(tag, s.SlugOptions)),
+			html.EscapeString(tag),
+		)
+	}
+
+	return template.HTML(b.String())
+}
+
+// data passed to templates and components when rendering a page
+func (s *Server) templateData(fileMetadata *parser.Meta, content template.HTML) map[string]any {
+	return map[string]any{
+		"site":    s.Site,
+		"data":    s.Data,
+		"page":    pageData(fileMetadata),
+		"title":   fileMetadata.Title,
+		"content": content,
+		"toc":     template.HTML(""),
+		"tags":    s.tagsHTML(fileMetadata.Tags),
+	}
+}
+
+// data passed to a component: the data of the page it is used in, with the
+// attributes of the component element bound as {{ $props.* }} on top of the
+// defaults declared in the component frontmatter under `props`
+func componentData(
+	data map[string]any,
+	component *parser.Meta,
+	element *html.Node,
+) map[string]any {
+	props := map[string]any{}
+
+	// attribute names are lowercased by the html parser, they are matched to
+	// the props declared in the frontmatter regardless of case so that
+	// declared props keep their name, ie: cardTitle
+	declared := map[string]string{}
+
+	if component.Frontmatter != nil {
+		defaults, _ := component.Frontmatter.Get("props")
+		for key, value := range utils.GetSafeValue[map[string]any](defaults) {
+			props[key] = value
+			declared[strings.ToLower(key)] = key
+		}
+	}
+
+	for _, attr := range element.Attr {
+		if attr.Key == COMPONENT_ATTR {
+			continue
+		}
+
+		key := attr.Key
+		if name, ok := declared[key]; ok {
+			key = name
+		}
+
+		props[key] = attr.Val
+	}
+
+	componentData := make(map[string]any, len(data)+1)
+	for key, value := range data {
+		componentData[key] = value
+	}
+
+	componentData["props"] = props
+
+	return componentData
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+func TestComponentData(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults map[string]any
+		attrs    []html.Attribute
+		want     map[string]any
+	}{
+		{
+			name:     "no props",
+			defaults: nil,
+			attrs:    nil,
+			want:     map[string]any{},
+		},
+		{
+			name:     "defaults",
+			defaults: map[string]any{"size": "small"},
+			attrs:    nil,
+			want:     map[string]any{"size": "small"},
+		},
+		{
+			name:     "attributes win over defaults",
+			defaults: map[string]any{"size": "small"},
+			attrs:    []html.Attribute{{Key: "size", Val: "large"}},
+			want:     map[string]any{"size": "large"},
+		},
+		{
+			name:     "lowercased attributes keep the case of the declared prop",
+			defaults: map[string]any{"cardTitle": "untitled"},
+			attrs:    []html.Attribute{{Key: "cardtitle", Val: "Hello"}},
+			want:     map[string]any{"cardTitle": "Hello"},
+		},
+		{
+			name:     "undeclared attributes are lowercase props",
+			defaults: map[string]any{"cardTitle": "untitled"},
+			attrs:    []html.Attribute{{Key: "subtitle", Val: "World"}},
+			want:     map[string]any{"cardTitle": "untitled", "subtitle": "World"},
+		},
+		{
+			name:     "the component marker is not a prop",
+			defaults: nil,
+			attrs:    []html.Attribute{{Key: COMPONENT_ATTR, Val: "card"}, {Key: "x", Val: "1"}},
+			want:     map[string]any{"x": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatter := parser.NewFrontmatter()
+			if tt.defaults != nil {
+				frontmatter.Set("props", tt.defaults)
+			}
+
+			data := componentData(
+				map[string]any{"page": "kept"},
+				&parser.Meta{Frontmatter: frontmatter},
+				&html.Node{Type: html.ElementNode, Data: "template", Attr: tt.attrs},
+			)
+
+			if data["page"] != "kept" {
+				t.Errorf("got page %v, want the data of the page", data["page"])
+			}
+
+			if !reflect.DeepEqual(data["props"], tt.want) {
+				t.Errorf("got props %#v, want %#v", data["props"], tt.want)
+			}
+		})
+	}
+}
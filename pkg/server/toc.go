@@ -0,0 +1,106 @@
+package server
+
+import (
+	htmltemplate "html/template"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/shreyaskaundinya/garlic/pkg/parser"
+)
+
+const (
+	// [[toc]] on its own line in the markdown is replaced by the table of
+	// contents of the page
+	TOC_MARKER_TEXT = "[[toc]]"
+
+	// the marker once rendered to html
+	TOC_MARKER = "<p>" + TOC_MARKER_TEXT + "</p>"
+)
+
+// render the markdown of a page to html, with the [[toc]] marker replaced
+func (s *Server) renderContent(page *parser.Meta) (string, error) {
+	content, err := s.Parser.Render(page.F)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.Contains(content.String(), TOC_MARKER) {
+		return content.String(), nil
+	}
+
+	toc, err := s.tocHTML(page)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(content.String(), TOC_MARKER, string(toc)), nil
+}
+
+// the table of contents of a page as nested lists linking to the headings,
+// exposed to templates as {{ $toc }}. Pages without headings (and list pages
+// without markdown) get an empty one.
+func (s *Server) tocHTML(page *parser.Meta) (htmltemplate.HTML, error) {
+	if page.F == nil {
+		return "", nil
+	}
+
+	entries := parser.TableOfContents(
+		parser.Headings(page.F),
+		s.Config.Toc.MinLevel,
+		s.Config.Toc.MaxLevel,
+	)
+
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	nav := &html.Node{
+		Type: html.ElementNode,
+		Data: "nav",
+		Attr: []html.Attribute{{Key: "class", Val: "toc"}},
+	}
+
+	nav.AppendChild(tocList(entries))
+
+	return renderNode(nav)
+}
+
+func tocList(entries []*parser.TocEntry) *html.Node {
+	ul := &html.Node{
+		Type: html.ElementNode,
+		Data: "ul",
+	}
+
+	for _, entry := range entries {
+		li := &html.Node{
+			Type: html.ElementNode,
+			Data: "li",
+		}
+
+		a := &html.Node{
+			Type: html.ElementNode,
+			Data: "a",
+		}
+
+		a.Attr = append(a.Attr, html.Attribute{
+			Key: "href",
+			Val: "#" + entry.ID,
+		})
+
+		a.AppendChild(&html.Node{
+			Type: html.TextNode,
+			Data: entry.Text,
+		})
+
+		li.AppendChild(a)
+
+		if len(entry.Children) > 0 {
+			li.AppendChild(tocList(entry.Children))
+		}
+
+		ul.AppendChild(li)
+	}
+
+	return ul
+}
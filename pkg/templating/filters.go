@@ -0,0 +1,141 @@
+package templating
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+// Filters returns the functions available to templates, values are piped in
+// as the last argument, ie: {{ $page.date | date "Jan 2, 2006" }}. The slugify
+// filter uses the slug options of the site so that links built in templates
+// match the paths of the generated pages.
+func Filters(slugOptions utils.SlugOptions) template.FuncMap {
+	return template.FuncMap{
+		"upper":    upper,
+		"lower":    lower,
+		"truncate": truncate,
+		"urlize":   urlize,
+		"slugify":  slugify(slugOptions),
+		"date":     date,
+		"isoDate":  isoDate,
+		"default":  defaultValue,
+		"join":     join,
+		"safeHTML": safeHTML,
+	}
+}
+
+func toString(value any) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+func upper(value any) string {
+	return strings.ToUpper(toString(value))
+}
+
+func lower(value any) string {
+	return strings.ToLower(toString(value))
+}
+
+// truncate cuts the value to length characters, adding an ellipsis when cut
+func truncate(length int, value any) string {
+	runes := []rune(toString(value))
+	if length < 0 || len(runes) <= length {
+		return string(runes)
+	}
+
+	return strings.TrimRightFunc(string(runes[:length]), unicode.IsSpace) + "…"
+}
+
+// urlize turns the value into a lowercase, url safe string
+func urlize(value any) string {
+	var b strings.Builder
+
+	lastDash := true
+	for _, r := range strings.ToLower(toString(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			lastDash = false
+		case !lastDash:
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// slugify turns the value into the url path of a taxonomy term, ie:
+// {{ range $page.tags }}<a href="/tags/{{ . | slugify }}">{{ . }}</a>{{ end }}
+func slugify(options utils.SlugOptions) func(value any) string {
+	return func(value any) string {
+		return utils.Slugify(toString(value), options)
+	}
+}
+
+// date formats a date using a go layout, values that are not dates are
+// returned as is
+func date(layout string, value any) string {
+	t, ok := utils.ParseTime(value)
+	if !ok {
+		return toString(value)
+	}
+
+	return t.Format(layout)
+}
+
+// isoDate formats a date as RFC 3339, ie: <time datetime="{{ $page.date | isoDate }}">
+func isoDate(value any) string {
+	t, ok := utils.ParseTime(value)
+	if !ok {
+		return toString(value)
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// default returns fallback when the value is empty
+func defaultValue(fallback any, value any) any {
+	if value == nil {
+		return fallback
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 {
+			return fallback
+		}
+	}
+
+	return value
+}
+
+func join(sep string, value any) string {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return toString(value)
+	}
+
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = toString(v.Index(i).Interface())
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// safeHTML marks the value as trusted html so that it is not escaped
+func safeHTML(value any) template.HTML {
+	return template.HTML(toString(value))
+}
@@ -0,0 +1,164 @@
+package templating
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+)
+
+var (
+	// {{ ... }} actions, including the {{- -}} trim markers
+	actionRegex = regexp.MustCompile(`(?s){{.*?}}`)
+
+	// inside an action: string literals (left untouched) or $variables
+	variableRegex = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`|\\$([A-Za-z_][A-Za-z0-9_]*)")
+
+	// variables declared by the template itself, ie: {{ $x := ... }} or
+	// {{ range $i, $x := ... }}
+	declarationRegex = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)\s*(?:,\s*\$([A-Za-z_][A-Za-z0-9_]*)\s*)?:=`)
+
+	// the keyword an action starts with, ie: range in {{- range ... }}
+	keywordRegex = regexp.MustCompile(`^{{(?:-\s)?\s*([a-z]+)\b`)
+
+	// blocks written without a pipeline, ie: {{ block "main" }}
+	blockRegex = regexp.MustCompile(`{{(-?\s*)block\s+("(?:[^"\\]|\\.)*")(\s*-?)}}`)
+)
+
+// Layer is one template of an inheritance chain, see RenderLayers
+type Layer struct {
+	Name string
+	Body []byte
+}
+
+// variables declared between an action opening a scope and its {{ end }}
+type scope struct {
+	vars map[string]bool
+
+	// the body of a define or block does not see the variables around it
+	isolated bool
+}
+
+// rewrite the globals used by templates ($page, $site, $content, ...) into
+// lookups on the root data ($.page, $.site, $.content, ...) so that they can be
+// used anywhere in a template without being declared. Variables declared by
+// the template are left alone within the scope declaring them.
+func rewriteGlobals(body string) string {
+	// blocks get the root data so that the globals work inside them
+	body = blockRegex.ReplaceAllString(body, "{{${1}block $2 $$$3}}")
+
+	scopes := []*scope{{vars: map[string]bool{}}}
+
+	declared := func(name string) bool {
+		for i := len(scopes) - 1; i >= 0; i-- {
+			if scopes[i].vars[name] {
+				return true
+			}
+
+			if scopes[i].isolated {
+				return false
+			}
+		}
+
+		return false
+	}
+
+	// actions are replaced in order, so the scopes follow the template
+	return actionRegex.ReplaceAllStringFunc(body, func(action string) string {
+		keyword := ""
+		if match := keywordRegex.FindStringSubmatch(action); match != nil {
+			keyword = match[1]
+		}
+
+		switch keyword {
+		case "range", "with", "if":
+			scopes = append(scopes, &scope{vars: map[string]bool{}})
+		case "define", "block":
+			scopes = append(scopes, &scope{vars: map[string]bool{}, isolated: true})
+		case "end":
+			if len(scopes) > 1 {
+				scopes = scopes[:len(scopes)-1]
+			}
+		}
+
+		for _, match := range declarationRegex.FindAllStringSubmatch(action, -1) {
+			for _, name := range match[1:] {
+				if name != "" {
+					scopes[len(scopes)-1].vars[name] = true
+				}
+			}
+		}
+
+		return variableRegex.ReplaceAllStringFunc(action, func(token string) string {
+			if token[0] != '$' || declared(token[1:]) {
+				return token
+			}
+
+			return "$." + token[1:]
+		})
+	})
+}
+
+// Parse compiles the body of a template or component, name is used in errors
+// and funcs are the functions available to it (see Filters)
+func Parse(name string, body []byte, funcs template.FuncMap) (*template.Template, error) {
+	t, err := template.New(name).
+		Funcs(funcs).
+		Parse(rewriteGlobals(string(body)))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+
+	return t, nil
+}
+
+// Execute runs a compiled template against data and returns the output
+func Execute(t *template.Template, data map[string]any) (string, error) {
+	var b bytes.Buffer
+
+	err := t.Execute(&b, data)
+	if err != nil {
+		return "", fmt.Errorf("error executing template %s: %w", t.Name(), err)
+	}
+
+	return b.String(), nil
+}
+
+// ParseLayers compiles an inheritance chain, layers go from the base layout
+// to the template extending it. The blocks of a layer override the blocks of
+// the layers before it, anything outside of the blocks of a layer other than
+// the base is ignored.
+func ParseLayers(layers []Layer, funcs template.FuncMap) (*template.Template, error) {
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("no template to parse")
+	}
+
+	t, err := Parse(layers[0].Name, layers[0].Body, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range layers[1:] {
+		_, err = t.New(layer.Name).Parse(rewriteGlobals(string(layer.Body)))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template %s: %w", layer.Name, err)
+		}
+	}
+
+	return t, nil
+}
+
+// Render parses and executes a template in one go
+func Render(name string, body []byte, data map[string]any, funcs template.FuncMap) (string, error) {
+	return RenderLayers([]Layer{{Name: name, Body: body}}, data, funcs)
+}
+
+// RenderLayers parses and executes an inheritance chain in one go
+func RenderLayers(layers []Layer, data map[string]any, funcs template.FuncMap) (string, error) {
+	t, err := ParseLayers(layers, funcs)
+	if err != nil {
+		return "", err
+	}
+
+	return Execute(t, data)
+}
@@ -0,0 +1,113 @@
+package templating
+
+import (
+	"testing"
+
+	"github.com/shreyaskaundinya/garlic/pkg/utils"
+)
+
+func TestRewriteGlobals(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "global",
+			body: `{{ $page.title }}`,
+			want: `{{ $.page.title }}`,
+		},
+		{
+			name: "trim markers",
+			body: `{{- $site.title -}}`,
+			want: `{{- $.site.title -}}`,
+		},
+		{
+			name: "root and dot are untouched",
+			body: `{{ $.page.title }} {{ .title }} {{ $ }}`,
+			want: `{{ $.page.title }} {{ .title }} {{ $ }}`,
+		},
+		{
+			name: "string literals are untouched",
+			body: "{{ printf \"$page %s\" `$site` $title }}",
+			want: "{{ printf \"$page %s\" `$site` $.title }}",
+		},
+		{
+			name: "declared variable",
+			body: `{{ $title := $page.title }}{{ $title }}`,
+			want: `{{ $title := $.page.title }}{{ $title }}`,
+		},
+		{
+			name: "assignment does not declare",
+			body: `{{ $title = 1 }}`,
+			want: `{{ $.title = 1 }}`,
+		},
+		{
+			name: "range variables are scoped to the range",
+			body: `{{ range $page := $pages }}{{ $page.title }}{{ end }}{{ $page.title }}`,
+			want: `{{ range $page := $.pages }}{{ $page.title }}{{ end }}{{ $.page.title }}`,
+		},
+		{
+			name: "range index and element",
+			body: `{{ range $i, $tag := $tags }}{{ $i }}{{ $tag }}{{ end }}{{ $tags }}`,
+			want: `{{ range $i, $tag := $.tags }}{{ $i }}{{ $tag }}{{ end }}{{ $.tags }}`,
+		},
+		{
+			name: "variables of outer scopes are visible",
+			body: `{{ $x := 1 }}{{ if $page }}{{ with $site }}{{ $x }}{{ end }}{{ end }}{{ $x }}`,
+			want: `{{ $x := 1 }}{{ if $.page }}{{ with $.site }}{{ $x }}{{ end }}{{ end }}{{ $x }}`,
+		},
+		{
+			name: "variables declared inside an if end with it",
+			body: `{{ if $page }}{{ $title := 1 }}{{ else }}{{ $title }}{{ end }}{{ $title }}`,
+			want: `{{ if $.page }}{{ $title := 1 }}{{ else }}{{ $title }}{{ end }}{{ $.title }}`,
+		},
+		{
+			name: "else if does not open a scope",
+			body: `{{ if $a }}{{ else if $b }}{{ $x := 1 }}{{ end }}{{ $x }}`,
+			want: `{{ if $.a }}{{ else if $.b }}{{ $x := 1 }}{{ end }}{{ $.x }}`,
+		},
+		{
+			name: "define does not see the variables around it",
+			body: `{{ $title := 1 }}{{ define "x" }}{{ $title }}{{ end }}{{ $title }}`,
+			want: `{{ $title := 1 }}{{ define "x" }}{{ $.title }}{{ end }}{{ $title }}`,
+		},
+		{
+			name: "block gets the root data",
+			body: `{{ block "main" }}{{ $content }}{{ end }}`,
+			want: `{{ block "main" $ }}{{ $.content }}{{ end }}`,
+		},
+		{
+			name: "block with trim markers",
+			body: `{{- block "main" -}}{{ $x := 1 }}{{ $x }}{{- end -}}{{ $x }}`,
+			want: `{{- block "main" $ -}}{{ $x := 1 }}{{ $x }}{{- end -}}{{ $.x }}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rewriteGlobals(tt.body)
+			if got != tt.want {
+				t.Errorf("rewriteGlobals(%q)\n got: %q\nwant: %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderScopedVariables(t *testing.T) {
+	data := map[string]any{
+		"page":  map[string]any{"title": "Home"},
+		"pages": []map[string]any{{"title": "a"}, {"title": "b"}},
+	}
+
+	body := []byte(`{{ range $page := $pages }}{{ $page.title }},{{ end }}{{ $page.title }}`)
+
+	got, err := Render("test", body, data, Filters(utils.DefaultSlugOptions))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "a,b,Home"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -20,3 +20,34 @@ func PathExists(path string) (bool, error) {
 func FileNameWithoutExtension(fileName string) string {
 	return strings.TrimSuffix(fileName, filepath.Ext(fileName))
 }
+
+// IsWithin reports whether path is parent or a folder (or file) inside it,
+// both are made absolute first
+func IsWithin(parent, path string) bool {
+	parent, err := filepath.Abs(parent)
+	if err != nil {
+		return false
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(parent, path)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// CleanPath is filepath.Clean keeping an empty path empty (instead of "."),
+// so that ./src, src/ and src all name the same folder
+func CleanPath(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	return filepath.Clean(filepath.FromSlash(path))
+}
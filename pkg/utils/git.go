@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// marks the start of a commit in the git log output, written by git for %x01.
+// Paths are separated by NUL bytes (-z) so that any file name can be read.
+const gitCommitPrefix = "\x01"
+
+// ErrGitNotFound is returned when the git binary is not installed
+var ErrGitNotFound = errors.New("git is not installed")
+
+// GitLastModified returns the date of the last commit touching each file of
+// the git repository dir belongs to, keyed by absolute path. Uncommitted files
+// are missing from it.
+func GitLastModified(dir string) (map[string]time.Time, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	git, err := exec.LookPath("git")
+	if err != nil {
+		return nil, ErrGitNotFound
+	}
+
+	out, err := exec.Command(git, "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+
+	root := strings.TrimSpace(string(out))
+
+	// newest commits first, so the first date seen for a file is its last
+	out, err = exec.Command(
+		git, "-C", root,
+		"-c", "core.quotepath=false",
+		"log",
+		"-z",
+		"--format=%x01%cI",
+		"--name-only",
+		"--no-renames",
+		"--", dir,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the git log: %w", err)
+	}
+
+	dates := map[string]time.Time{}
+
+	var commitDate time.Time
+
+	for _, field := range strings.Split(string(out), "\x00") {
+		// the first path of a commit follows the newline ending its date
+		field = strings.TrimPrefix(field, "\n")
+
+		if date, ok := strings.CutPrefix(field, gitCommitPrefix); ok {
+			commitDate, err = time.Parse(time.RFC3339, date)
+			if err != nil {
+				return nil, fmt.Errorf("error reading the git log: %w", err)
+			}
+			continue
+		}
+
+		if field == "" {
+			continue
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(field))
+		if _, ok := dates[path]; !ok {
+			dates[path] = commitDate
+		}
+	}
+
+	return dates, nil
+}
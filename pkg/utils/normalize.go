@@ -0,0 +1,36 @@
+package utils
+
+import "fmt"
+
+// NormalizeValue converts decoded yaml/toml values into types that are easy
+// to work with, ie: map[any]any becomes map[string]any (recursively)
+func NormalizeValue(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = NormalizeValue(val)
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[key] = NormalizeValue(val)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, val := range v {
+			s[i] = NormalizeValue(val)
+		}
+		return s
+	case []map[string]any:
+		s := make([]any, len(v))
+		for i, val := range v {
+			s[i] = NormalizeValue(val)
+		}
+		return s
+	}
+
+	return value
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type SlugOptions struct {
+	// lowercase the slug
+	Lowercase bool
+
+	// replace accented latin letters with their ascii base, ie: é -> e
+	Transliterate bool
+
+	// used between words
+	Separator string
+}
+
+var DefaultSlugOptions = SlugOptions{
+	Lowercase:     true,
+	Transliterate: true,
+	Separator:     "-",
+}
+
+// latin letters that do not decompose into a base letter and a mark
+var transliterations = strings.NewReplacer(
+	"ß", "ss", "ẞ", "SS",
+	"æ", "ae", "Æ", "AE",
+	"œ", "oe", "Œ", "OE",
+	"ø", "o", "Ø", "O",
+	"đ", "d", "Đ", "D",
+	"ð", "d", "Ð", "D",
+	"ł", "l", "Ł", "L",
+	"þ", "th", "Þ", "TH",
+	"ı", "i",
+)
+
+// Slugify turns a value into a single url path segment: letters and digits
+// are kept, everything else (spaces, slashes, dots, ...) becomes the
+// separator. The slug of a value without letters or digits is empty.
+func Slugify(value string, options SlugOptions) string {
+	if options.Transliterate {
+		value = transliterations.Replace(value)
+	}
+
+	value = norm.NFD.String(value)
+
+	var b strings.Builder
+
+	// the letter the marks that follow belong to
+	var letter rune
+
+	pendingSeparator := false
+	for _, r := range value {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// marks of latin letters are dropped when transliterating, the
+			// marks of other scripts are part of the letter (ie: ブ)
+			if b.Len() > 0 && !pendingSeparator &&
+				(!options.Transliterate || !unicode.Is(unicode.Latin, letter)) {
+				b.WriteRune(r)
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if pendingSeparator && b.Len() > 0 {
+				b.WriteString(options.Separator)
+			}
+			pendingSeparator = false
+			letter = r
+
+			if options.Lowercase {
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		default:
+			pendingSeparator = true
+		}
+	}
+
+	return norm.NFC.String(b.String())
+}
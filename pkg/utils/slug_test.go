@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	keepCase := DefaultSlugOptions
+	keepCase.Lowercase = false
+
+	keepAccents := DefaultSlugOptions
+	keepAccents.Transliterate = false
+
+	underscore := DefaultSlugOptions
+	underscore.Separator = "_"
+
+	tests := []struct {
+		name    string
+		value   string
+		options SlugOptions
+		want    string
+	}{
+		{name: "plain", value: "go", options: DefaultSlugOptions, want: "go"},
+		{name: "lowercased", value: "Go Lang", options: DefaultSlugOptions, want: "go-lang"},
+		{name: "case kept", value: "Go Lang", options: keepCase, want: "Go-Lang"},
+		{name: "empty", value: "", options: DefaultSlugOptions, want: ""},
+		{name: "punctuation only", value: "?!/ ...", options: DefaultSlugOptions, want: ""},
+		{name: "punctuation between words", value: "C++ & Go", options: DefaultSlugOptions, want: "c-go"},
+		{name: "leading and trailing punctuation", value: "  --hello, world!--  ", options: DefaultSlugOptions, want: "hello-world"},
+		{name: "slashes and dots", value: "v1.2/notes", options: DefaultSlugOptions, want: "v1-2-notes"},
+		{name: "digits", value: "2024 review", options: DefaultSlugOptions, want: "2024-review"},
+		{name: "underscore separator", value: "Go Stuff", options: underscore, want: "go_stuff"},
+		{name: "accents transliterated", value: "Crème Brûlée", options: DefaultSlugOptions, want: "creme-brulee"},
+		{name: "accents kept", value: "Crème Brûlée", options: keepAccents, want: "crème-brûlée"},
+		{name: "decomposed accents kept", value: "Crème", options: keepAccents, want: "crème"},
+		{name: "letters without a base letter", value: "Straße Ærø Łódź", options: DefaultSlugOptions, want: "strasse-aero-lodz"},
+		{name: "mark without a letter", value: "́go", options: keepAccents, want: "go"},
+		{name: "non latin scripts are kept", value: "日本語 ブログ", options: DefaultSlugOptions, want: "日本語-ブログ"},
+		{name: "cyrillic lowercased", value: "Привет Мир", options: DefaultSlugOptions, want: "привет-мир"},
+		{name: "emoji dropped", value: "go 🚀 fast", options: DefaultSlugOptions, want: "go-fast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.value, tt.options)
+			if got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
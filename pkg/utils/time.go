@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// layouts tried in order when parsing dates from frontmatter and config
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"02 Jan 2006",
+}
+
+// ParseTime converts a decoded frontmatter value into a time, dates without a
+// timezone are treated as UTC
+func ParseTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, !v.IsZero()
+	case string:
+		v = strings.TrimSpace(v)
+		for _, layout := range dateLayouts {
+			t, err := time.Parse(layout, v)
+			if err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -55,6 +55,9 @@ hugo new theme mytheme; echo "theme = 'mytheme'" >> hugo.toml; cd /tmp/bench
 # create the content folder for garlic
 mkdir -p /tmp/bench/garlic/src/content
 
+# seed the templates, components and assets
+cd /tmp/bench/garlic && ./garlic new /tmp/bench/garlic
+
 # clean content/* dirs
 echo ""
 echo "Cleaning content directories"
@@ -87,6 +90,6 @@ hyperfine --ignore-failure -p 'sync' -w $warm \
   "cd /tmp/bench/hugo && hugo" \
   "cd /tmp/bench/anna && ./anna -r \"site/\"" \
   "cd /tmp/bench/saaru && ./saaru --base-path ./docs" \
-  "cd /tmp/bench/garlic && ./garlic --src-folder /tmp/bench/garlic/src --dest-folder /tmp/bench/garlic/dest --seed-files"
+  "cd /tmp/bench/garlic && ./garlic build --src-folder /tmp/bench/garlic/src --dest-folder /tmp/bench/garlic/dest"
 
 echo ""
\ No newline at end of file
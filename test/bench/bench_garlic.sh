@@ -32,6 +32,9 @@ cd /tmp/bench/garlic && go build -o garlic && mv garlic /tmp/bench/garlic/garlic
 # create the content folder
 mkdir -p /tmp/bench/garlic/src/content
 
+# seed the templates, components and assets
+cd /tmp/bench/garlic && ./garlic new /tmp/bench/garlic
+
 ls -la /tmp/bench/garlic/src/
 
 
@@ -56,7 +59,7 @@ echo ""
 # "cd /tmp/bench/sapling/benchmark && ./../sapling run" \
 
 hyperfine -p 'sync' -w $warm \
-  "cd /tmp/bench/garlic && ./garlic --src-folder /tmp/bench/garlic/src --dest-folder /tmp/bench/garlic/dest --seed-files"
+  "cd /tmp/bench/garlic && ./garlic build --src-folder /tmp/bench/garlic/src --dest-folder /tmp/bench/garlic/dest"
 echo ""
 
 